@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/api/v1/auth/callback/{provider}": {
             "get": {
-                "description": "Exchanges authorization code for a VanishVault JWT access token.",
+                "description": "Exchanges authorization code for a VanishVault JWT access token and refresh token, proving possession of the PKCE code verifier and checking the OIDC nonce.",
                 "produces": [
                     "application/json"
                 ],
@@ -37,7 +37,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "google or github",
+                        "description": "google, github or a configured OIDC provider name",
                         "name": "provider",
                         "in": "path",
                         "required": true
@@ -51,7 +51,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "CSRF state",
+                        "description": "Single-use state issued by the login endpoint",
                         "name": "state",
                         "in": "query",
                         "required": true
@@ -59,80 +59,89 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Link confirmation page, when the login links a provider to an existing user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to the login redirect with the tokens in the URL fragment",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CallbackResponse"
+                            "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized or invalid state",
+                        "description": "Unauthorized, invalid state or nonce",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "500": {
                         "description": "Failed to process authentication",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/login/{provider}": {
+        "/api/v1/auth/device": {
             "get": {
-                "description": "Redirects to the auth provider or returns the URL based on the Accept header.",
+                "description": "HTML page where the user enters the code shown by the device and picks a provider to log in with. The login approves the device instead of returning tokens to the browser.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Initiate OAuth2 Login",
+                "summary": "Device Verification Page",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "github"
-                        ],
                         "type": "string",
-                        "description": "Auth Provider",
-                        "name": "provider",
-                        "in": "path",
-                        "required": true
+                        "description": "User code shown by the device",
+                        "name": "user_code",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns JSON with auth URL",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.LoginResponse"
-                        }
-                    },
-                    "307": {
-                        "description": "Temporary Redirect to Provider",
+                        "description": "Verification page",
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "400": {
-                        "description": "Invalid provider",
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/device/code": {
+            "post": {
+                "description": "Starts an OAuth 2.0 device authorization request (RFC 8628) for clients without a browser, such as a terminal. The client shows the user code and verification URI, the user logs in with any provider on a second device, and the client polls the token endpoint with the device code.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Start Device Login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeviceCodeResponseDto"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/refresh": {
+        "/api/v1/auth/device/token": {
             "post": {
-                "description": "Generates a new Access Token using a valid Refresh Token, keeping the session active without re-login.",
+                "description": "Polled by the device with its device code. Until the user approves the request it answers 400 with ` + "`" + `authorization_pending` + "`" + `, or ` + "`" + `slow_down` + "`" + ` when polled faster than the advertised interval; ` + "`" + `expired_token` + "`" + ` means the device must start over. Once approved, the device receives a new session's Access/Refresh Token pair, exactly once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -142,286 +151,1049 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Refresh JWT Token",
+                "summary": "Poll Device Login",
                 "parameters": [
                     {
-                        "description": "Object containing the refresh_token",
+                        "description": "Object containing the device_code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeviceTokenRequestDto"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "New Access Token",
+                        "description": "Access and Refresh Tokens",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CallbackResponseDto"
                         }
                     },
-                    "401": {
-                        "description": "Invalid or expired Refresh Token",
+                    "400": {
+                        "description": "authorization_pending, slow_down or expired_token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate session token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
             }
         },
-        "/api/v1/rooms": {
+        "/api/v1/auth/identities": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lists rooms available to the user (those created by them or public, depending on rules).",
+                "description": "Lists the providers the current user can sign in with.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rooms"
+                    "Auth"
                 ],
-                "summary": "List Rooms",
+                "summary": "List Linked Identities",
                 "responses": {
                     "200": {
-                        "description": "List of rooms",
+                        "description": "List of linked identities",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "object",
-                                "additionalProperties": true
+                                "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.IdentityResponseDto"
                             }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list identities",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/identities/{provider}": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new encrypted private room for ephemeral data sharing.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the provider authorization URL to open in a browser. Once the user logs in there and confirms on the page the callback shows, that identity is linked to the current user, so either provider signs in to the same account.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rooms"
+                    "Auth"
                 ],
-                "summary": "Create Secure Room",
+                "summary": "Link Identity",
                 "parameters": [
                     {
-                        "description": "Room data (name, optional password, expiration time)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "string",
+                        "description": "google, github or a configured OIDC provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created room details",
+                    "200": {
+                        "description": "Provider authorization URL",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.LoginResponseDto"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid provider",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/rooms/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns metadata for a specific room by ID.",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Removes the current user's identity with the provider. The last identity cannot be removed. Existing sessions stay valid.",
                 "tags": [
-                    "Rooms"
+                    "Auth"
                 ],
-                "summary": "Get Room Details",
+                "summary": "Unlink Identity",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID (UUID)",
-                        "name": "id",
+                        "description": "Provider name",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Room data",
+                    "204": {
+                        "description": "No Content - Identity unlinked"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "404": {
-                        "description": "Room not found",
+                        "description": "Identity not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot unlink the last identity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unlink identity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/api/v1/auth/link/confirm": {
+            "post": {
+                "description": "Submitted by the confirmation page the callback shows for link flows. Links the provider identity to the account that started the flow. Each token can be used once.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
                 ],
-                "description": "Permanently removes a room and all associated secrets (Immediate purge).",
                 "tags": [
-                    "Rooms"
+                    "Auth"
                 ],
-                "summary": "Delete Room",
+                "summary": "Confirm Identity Link",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID (UUID)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Pending link token from the confirmation page",
+                        "name": "token",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content - Room successfully deleted"
+                    "200": {
+                        "description": "Identity linked page",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    "403": {
-                        "description": "No permission to delete this room",
+                    "400": {
+                        "description": "Invalid or expired link page",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Identity already linked page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to link identity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
             }
         },
-        "/api/v1/rooms/{id}/join": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Allows an authenticated user to join a room (may require password in body).",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/auth/login/{provider}": {
+            "get": {
+                "description": "Redirects to the auth provider or returns the URL based on the Accept header. The request carries a PKCE (S256) code challenge and, for OIDC providers, a nonce, all kept server side and keyed by the single-use state, so the URL can be opened in any browser. When ` + "`" + `redirect` + "`" + ` is given, the callback sends the user there with the tokens in the URL fragment; it must be a loopback URL or share the origin of the API or an allowed redirect. When ` + "`" + `user_code` + "`" + ` is given, the login approves that device authorization request instead.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rooms"
+                    "Auth"
                 ],
-                "summary": "Join Room",
+                "summary": "Initiate OAuth2 Login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID",
-                        "name": "id",
+                        "description": "Auth Provider: google, github or a configured OIDC provider name",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Room password (if applicable)",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "string",
+                        "description": "Where to send the user with their tokens after login",
+                        "name": "redirect",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User code of the device authorization request to approve",
+                        "name": "user_code",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Join confirmation",
+                        "description": "Returns JSON with auth URL",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.LoginResponseDto"
                         }
                     },
-                    "403": {
-                        "description": "Incorrect password or access denied",
+                    "307": {
+                        "description": "Temporary Redirect to Provider",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider, redirect or user code",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
             }
         },
-        "/api/v1/rooms/{id}/leave": {
+        "/api/v1/auth/logout": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes the current user from the room's participant list.",
+                "description": "Revokes the Access Token used in the request together with its session and Refresh Tokens.",
                 "tags": [
-                    "Rooms"
-                ],
-                "summary": "Leave Room",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Room ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Auth"
                 ],
+                "summary": "Logout",
                 "responses": {
-                    "200": {
-                        "description": "Leave confirmation",
+                    "204": {
+                        "description": "No Content - Tokens revoked"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Exchanges a valid Refresh Token for a new Access/Refresh Token pair. Refresh Tokens are single use: presenting an already used token revokes the whole session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh JWT Token",
+                "parameters": [
+                    {
+                        "description": "Object containing the refresh_token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.RefreshRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New Access and Refresh Tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CallbackResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired or reused Refresh Token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to refresh session",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the active login sessions of the current user, including device and activity metadata.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List Sessions",
+                "responses": {
+                    "200": {
+                        "description": "List of active sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SessionResponseDto"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list sessions",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes all sessions of the current user, including the one used for this request.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log Out Everywhere",
+                "responses": {
+                    "204": {
+                        "description": "No Content - All sessions revoked"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke sessions",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes one session of the current user, invalidating its Access and Refresh Tokens immediately.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Session revoked"
+                    },
+                    "400": {
+                        "description": "Invalid session ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke session",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the current user's tokens that have not been revoked, including when each was last used, so stale tokens can be spotted and revoked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List Personal Access Tokens",
+                "responses": {
+                    "200": {
+                        "description": "List of tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.PersonalAccessTokenResponseDto"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Personal access tokens cannot list tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a token for automation such as CI pipelines, which cannot complete an OAuth redirect. Send it as ` + "`" + `Authorization: Bearer vvpat_...` + "`" + `; it is limited to the granted scopes and cannot manage the account, sessions or tokens. The token is only shown in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Create Personal Access Token",
+                "parameters": [
+                    {
+                        "description": "Token name, scopes and optional expiry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreatePersonalAccessTokenRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreatePersonalAccessTokenResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Personal access tokens cannot create tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/tokens/{tokenId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes one of the current user's tokens. It stops working immediately.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke Personal Access Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID (UUID)",
+                        "name": "tokenId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Token revoked"
+                    },
+                    "400": {
+                        "description": "Invalid token ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Personal access tokens cannot revoke tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the account the access token belongs to: its email, linked providers and room counts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Current User",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.AccountResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to load account",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the account in two steps. Called without ` + "`" + `confirmation_token` + "`" + `, it returns a short-lived token bound to the chosen room policy. Called again with that token and the same policy, it permanently deletes the account along with its identities, sessions, memberships and secrets. With ` + "`" + `transfer` + "`" + `, each owned room goes to its longest standing member with the highest role; rooms without other members are deleted. With ` + "`" + `delete` + "`" + `, every owned room is deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Delete Current User",
+                "parameters": [
+                    {
+                        "description": "Room policy and, for the second step, the confirmation token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeleteAccountRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Confirmation required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeleteAccountConfirmationDto"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content - Account deleted"
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid or expired confirmation token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete account",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rooms": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists rooms available to the user (those created by them or public, depending on rules).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "List Rooms",
+                "responses": {
+                    "200": {
+                        "description": "List of rooms",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new encrypted private room for ephemeral data sharing. The creator becomes the room admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "Create Secure Room",
+                "parameters": [
+                    {
+                        "description": "Room data (name, optional access code, expiration time)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreateRoomRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created room details",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.RoomResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create room",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rooms/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns metadata for a specific room by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "Get Room Details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently removes a room and all associated secrets (Immediate purge).",
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "Delete Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Room successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid room ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "No permission to delete this room",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete room",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rooms/{id}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows an authenticated user to join a room (may require password in body).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "Join Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room password (if applicable)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Join confirmation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password or access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rooms/{id}/leave": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the current user from the room's participant list.",
+                "tags": [
+                    "Rooms"
+                ],
+                "summary": "Leave Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Leave confirmation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
@@ -477,7 +1249,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Stores a new encrypted secret (AES-256) within a specific room.",
+                "description": "Stores a new encrypted secret within a specific room. Plaintext ` + "`" + `content` + "`" + ` is encrypted by the server (AES-256-GCM under the room data key). For zero-knowledge secrets the client sends ` + "`" + `encrypted_content` + "`" + `, ` + "`" + `nonce` + "`" + ` and ` + "`" + `algorithm` + "`" + ` instead, and the server stores the ciphertext without ever holding the key. Secrets burn after ` + "`" + `max_views` + "`" + ` reads (1 by default) or once ` + "`" + `expires_at` + "`" + ` has passed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,13 +1269,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Secret content and visibility settings",
+                        "description": "Secret content",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreateSecretRequestDto"
                         }
                     }
                 ],
@@ -511,22 +1282,37 @@ const docTemplate = `{
                     "201": {
                         "description": "Created secret metadata (ID, date)",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SecretResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "403": {
-                        "description": "User is not a member of the room",
+                        "description": "User is not a member of the room or cannot write to it",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "404": {
-                        "description": "Room not found",
+                        "description": "Room not found, inactive or expired",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to store secret",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
@@ -539,7 +1325,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves and decrypts the content of a specific secret. May trigger \"Burn on Read\" rule (delete after reading).",
+                "description": "Retrieves and decrypts the content of a specific secret. Each read consumes one of the secret's remaining views in the same statement, and the secret is burned when none are left, so it is never revealed more than ` + "`" + `max_views` + "`" + ` times (\"Burn on Read\"). Expired secrets, and secrets of inactive or expired rooms, are treated as burned. Client encrypted secrets are returned as stored in ` + "`" + `encrypted_content` + "`" + `, ` + "`" + `nonce` + "`" + ` and ` + "`" + `algorithm` + "`" + `, since the server never holds their key.",
                 "produces": [
                     "application/json"
                 ],
@@ -565,17 +1351,39 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Decrypted secret content",
+                        "description": "Decrypted secret content, or ciphertext for client encrypted secrets",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SecretContentResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid room or secret ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied to the room",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     },
                     "404": {
-                        "description": "Secret not found or already expired",
+                        "description": "Secret not found, already burned or expired",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read secret",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
@@ -595,13 +1403,13 @@ const docTemplate = `{
                     "200": {
                         "description": "Service is up and running",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.HealthCheckResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.HealthCheckResponseDto"
                         }
                     },
                     "503": {
                         "description": "Service or dependencies are down",
                         "schema": {
-                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto"
                         }
                     }
                 }
@@ -609,12 +1417,47 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CallbackResponse": {
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.AccountResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "joined_rooms": {
+                    "type": "integer"
+                },
+                "owned_rooms": {
+                    "type": "integer"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CallbackResponseDto": {
             "type": "object",
             "properties": {
                 "expiry_at": {
                     "type": "integer"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "refresh_token_expiry_at": {
+                    "type": "integer"
+                },
                 "token": {
                     "type": "string"
                 },
@@ -623,7 +1466,187 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponse": {
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreatePersonalAccessTokenRequestDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "ci-deploy"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rooms:read",
+                        "secrets:write"
+                    ]
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreatePersonalAccessTokenResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string",
+                    "example": "vvpat_..."
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreateRoomRequestDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "access_code": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 4
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.CreateSecretRequestDto": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "AES-256-GCM"
+                    ],
+                    "example": "AES-256-GCM"
+                },
+                "content": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "encrypted_content": {
+                    "type": "string",
+                    "maxLength": 87400
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_views": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 3
+                },
+                "nonce": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeleteAccountConfirmationDto": {
+            "type": "object",
+            "properties": {
+                "confirmation_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "owned_rooms": {
+                    "type": "integer"
+                },
+                "room_policy": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeleteAccountRequestDto": {
+            "type": "object",
+            "required": [
+                "room_policy"
+            ],
+            "properties": {
+                "confirmation_token": {
+                    "type": "string"
+                },
+                "room_policy": {
+                    "type": "string",
+                    "enum": [
+                        "transfer",
+                        "delete"
+                    ]
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeviceCodeResponseDto": {
+            "type": "object",
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "user_code": {
+                    "type": "string"
+                },
+                "verification_uri": {
+                    "type": "string"
+                },
+                "verification_uri_complete": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.DeviceTokenRequestDto": {
+            "type": "object",
+            "required": [
+                "device_code"
+            ],
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.ErrorResponseDto": {
             "type": "object",
             "properties": {
                 "code": {
@@ -637,7 +1660,7 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.HealthCheckResponse": {
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.HealthCheckResponseDto": {
             "type": "object",
             "properties": {
                 "code": {
@@ -654,18 +1677,201 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.LoginResponse": {
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.IdentityResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.LoginResponseDto": {
             "type": "object",
             "properties": {
                 "url": {
                     "type": "string"
                 }
             }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.PersonalAccessTokenResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.RefreshRequestDto": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.RoomResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "has_access_code": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SecretContentResponseDto": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "AES-256-GCM"
+                },
+                "burned_at": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "encrypted_content": {
+                    "type": "string"
+                },
+                "encryption_mode": {
+                    "type": "string",
+                    "example": "server"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nonce": {
+                    "type": "string"
+                },
+                "remaining_views": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SecretResponseDto": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "AES-256-GCM"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "encryption_mode": {
+                    "type": "string",
+                    "example": "server"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_views": {
+                    "type": "integer"
+                },
+                "remaining_views": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_TheCodeBreakerK_vanish-vault-api_internal_dto.SessionResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "BearerAuth": {
-            "description": "Type \"Bearer \" followed by your JWT token.",
+            "description": "Type \"Bearer \" followed by your JWT token or personal access token.",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -680,6 +1886,10 @@ const docTemplate = `{
             "description": "Secure identity verification and session management via OAuth2 providers and JWT issuance.",
             "name": "Auth"
         },
+        {
+            "description": "Profile and self-service deletion of the authenticated user's account.",
+            "name": "Account"
+        },
         {
             "description": "Management of private encrypted communication spaces, including access control and lifecycle.",
             "name": "Rooms"
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/worker"
+	"go.uber.org/zap"
+)
+
+var errKeysUsage = errors.New("usage: vanish-vault-api keys <generate|rotate>")
+
+// runKeysCommand implements the "keys" subcommand used to manage master key rotation.
+// Errors are returned rather than logged fatally so deferred cleanup runs before the process exits.
+//
+//	keys generate  prints a new random master key to append to MASTER_KEY as the next version
+//	keys rotate    re-wraps every room data key under the current master key version
+func runKeysCommand(ctx context.Context, cfg *configs.Conf, log *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return errKeysUsage
+	}
+
+	switch args[0] {
+	case "generate":
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("generate master key: %w", err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+		return nil
+	case "rotate":
+		return rotateKeys(ctx, cfg, log)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errKeysUsage, args[0])
+	}
+}
+
+func rotateKeys(ctx context.Context, cfg *configs.Conf, log *zap.Logger) error {
+	keys, err := service.NewKeyProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("load master key: %w", err)
+	}
+
+	encryptor := service.NewEncryptor(keys)
+	version, err := encryptor.CurrentKeyVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("load master key: %w", err)
+	}
+
+	dbPool := configs.NewDatabase(ctx, cfg, log)
+	defer dbPool.Close()
+
+	rewrapper := worker.NewKeyRewrapper(repository.NewStore(dbPool), encryptor, log, cfg.KeyRewrapBatchSize)
+	result, err := rewrapper.RewrapAll(ctx)
+	if err != nil {
+		return fmt.Errorf("rotate master key: %w", err)
+	}
+
+	if result.Failed > 0 {
+		return fmt.Errorf("%d room keys could not be re-wrapped to version %d (%d re-wrapped)",
+			result.Failed, version, result.Rewrapped)
+	}
+
+	log.Info("Master key rotation complete",
+		zap.Int32("key_version", version),
+		zap.Int("rewrapped", result.Rewrapped),
+	)
+
+	return nil
+}
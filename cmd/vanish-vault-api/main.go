@@ -3,11 +3,19 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 
 	// Importing the docs package to register Swagger documentation
 	_ "github.com/TheCodeBreakerK/vanish-vault-api/api/docs"
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/router"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/scheduler"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/worker"
+	"go.uber.org/zap"
 )
 
 // @title           VanishVault API
@@ -27,7 +35,7 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer " followed by your JWT token.
+// @description Type "Bearer " followed by your JWT token or personal access token.
 
 // @tag.name         Infra
 // @tag.description  Endpoints for system health monitoring, diagnostic checks, and operational status.
@@ -35,6 +43,9 @@ import (
 // @tag.name         Auth
 // @tag.description  Secure identity verification and session management via OAuth2 providers and JWT issuance.
 
+// @tag.name         Account
+// @tag.description  Profile and self-service deletion of the authenticated user's account.
+
 // @tag.name         Rooms
 // @tag.description  Management of private encrypted communication spaces, including access control and lifecycle.
 
@@ -48,8 +59,20 @@ func main() {
 	defer log.Sync()
 
 	cfg := configs.LoadConfig(log)
+	if cfg == nil {
+		log.Fatal("Failed to load configuration")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	ctx := context.Background()
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		if err := runKeysCommand(ctx, cfg, log, os.Args[2:]); err != nil {
+			stop()
+			log.Fatal("Keys command failed", zap.Error(err))
+		}
+		return
+	}
 
 	dbPool := configs.NewDatabase(ctx, cfg, log)
 	defer dbPool.Close()
@@ -57,6 +80,39 @@ func main() {
 	rdb := configs.NewRedisClient(ctx, cfg, log)
 	defer rdb.Close()
 
-	appRouter := router.NewRouter(cfg, log, dbPool, rdb)
-	appRouter.Setup()
+	keys, err := service.NewKeyProvider(cfg)
+	if err != nil {
+		log.Fatal("Failed to load master key", zap.Error(err))
+	}
+
+	encryptor := service.NewEncryptor(keys)
+
+	store := repository.NewStore(dbPool)
+	rewrapper := worker.NewKeyRewrapper(store, encryptor, log, cfg.KeyRewrapBatchSize)
+	reaper := worker.NewReaper(store, log, cfg.PurgeGracePeriod, cfg.PATIdleTimeout)
+
+	jobs := scheduler.New(rdb, log, cfg.SchedulerLeaseTTL)
+	jobs.Register(scheduler.Job{Name: "key-rewrap", Interval: cfg.KeyRewrapInterval, Run: rewrapper.RunOnce})
+	jobs.Register(scheduler.Job{Name: "reaper", Interval: cfg.ReaperInterval, Run: reaper.RunOnce})
+	jobs.Start(ctx)
+
+	appRouter := router.NewRouter(cfg, log, dbPool, rdb, encryptor)
+	serveErr := appRouter.Setup(ctx)
+	if serveErr != nil {
+		log.Error("Server stopped unexpectedly", zap.Error(serveErr))
+		stop()
+	}
+
+	// Background jobs stop with the signal context; wait for them before the deferred
+	// Redis and database closes run.
+	jobs.Wait()
+
+	if serveErr != nil {
+		// Exit non-zero so orchestrators and restart policies see the crash. log.Fatal skips
+		// deferred calls, so the connections are closed first.
+		_ = rdb.Close()
+		dbPool.Close()
+		log.Fatal("Shutdown after server failure")
+	}
+	log.Info("Shutdown complete")
 }
@@ -3,6 +3,8 @@ package configs
 
 import (
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -12,17 +14,44 @@ import (
 type Conf struct {
 	GinMode string `mapstructure:"GIN_MODE"`
 
+	PublicBaseURL string `mapstructure:"PUBLIC_BASE_URL"`
+	publicBaseURL *url.URL
+
+	HTTPAddr              string        `mapstructure:"HTTP_ADDR"`
+	HTTPReadTimeout       time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTPReadHeaderTimeout time.Duration `mapstructure:"HTTP_READ_HEADER_TIMEOUT"`
+	HTTPWriteTimeout      time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout       time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout       time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+
+	TLSCertFile     string `mapstructure:"TLS_CERT_FILE"`
+	TLSKeyFile      string `mapstructure:"TLS_KEY_FILE"`
+	TLSClientCAFile string `mapstructure:"TLS_CLIENT_CA_FILE"`
+
 	DBHost     string `mapstructure:"POSTGRES_HOST"`
 	DBPort     string `mapstructure:"POSTGRES_PORT"`
 	DBName     string `mapstructure:"POSTGRES_DB"`
 	DBUser     string `mapstructure:"POSTGRES_USER"`
 	DBPassword string `mapstructure:"POSTGRES_PASSWORD"`
 
-	GoogleClientID string `mapstructure:"GOOGLE_CLIENT_ID"`
-	GoogleSecret   string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GoogleClientID    string `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleSecret      string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GoogleRedirectURL string `mapstructure:"GOOGLE_REDIRECT_URL"`
+
+	GithubClientID    string `mapstructure:"GITHUB_CLIENT_ID"`
+	GithubSecret      string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	GithubRedirectURL string `mapstructure:"GITHUB_REDIRECT_URL"`
+
+	OAuthStateTTL         time.Duration `mapstructure:"OAUTH_STATE_TTL"`
+	OAuthAllowedRedirects []string      `mapstructure:"OAUTH_ALLOWED_REDIRECTS"`
+
+	DeviceCodeTTL      time.Duration `mapstructure:"DEVICE_CODE_TTL"`
+	DevicePollInterval time.Duration `mapstructure:"DEVICE_POLL_INTERVAL"`
+
+	AccountDeletionTTL time.Duration `mapstructure:"ACCOUNT_DELETION_TTL"`
 
-	GithubClientID string `mapstructure:"GITHUB_CLIENT_ID"`
-	GithubSecret   string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	OIDCProviderNames string             `mapstructure:"OIDC_PROVIDERS"`
+	OIDCProviders     []OIDCProviderConf `mapstructure:"-"`
 
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
@@ -30,6 +59,22 @@ type Conf struct {
 
 	JWTSecret          string `mapstructure:"JWT_SECRET"`
 	JWTExpirationHours int    `mapstructure:"JWT_EXPIRATION_HOURS"`
+
+	RefreshTokenExpirationHours int `mapstructure:"REFRESH_TOKEN_EXPIRATION_HOURS"`
+
+	MasterKey        string `mapstructure:"MASTER_KEY"`
+	MasterKeyFile    string `mapstructure:"MASTER_KEY_FILE"`
+	MasterKeyVersion int32  `mapstructure:"MASTER_KEY_VERSION"`
+
+	KeyRewrapInterval  time.Duration `mapstructure:"KEY_REWRAP_INTERVAL"`
+	KeyRewrapBatchSize int32         `mapstructure:"KEY_REWRAP_BATCH_SIZE"`
+
+	ReaperInterval   time.Duration `mapstructure:"REAPER_INTERVAL"`
+	PurgeGracePeriod time.Duration `mapstructure:"PURGE_GRACE_PERIOD"`
+
+	PATIdleTimeout time.Duration `mapstructure:"PAT_IDLE_TIMEOUT"`
+
+	SchedulerLeaseTTL time.Duration `mapstructure:"SCHEDULER_LEASE_TTL"`
 }
 
 // LoadConfig reads the .env file and unmarshals it into the Conf struct.
@@ -38,11 +83,37 @@ func LoadConfig(log *zap.Logger) *Conf {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("HTTP_ADDR", ":8080")
+	viper.SetDefault("HTTP_READ_TIMEOUT", "15s")
+	viper.SetDefault("HTTP_READ_HEADER_TIMEOUT", "5s")
+	viper.SetDefault("HTTP_WRITE_TIMEOUT", "30s")
+	viper.SetDefault("HTTP_IDLE_TIMEOUT", "120s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "25s")
+
 	viper.SetDefault("POSTGRES_PORT", "5432")
 
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_ADDR", "redis:${REDIS_PORT}")
 
+	viper.SetDefault("REFRESH_TOKEN_EXPIRATION_HOURS", 720)
+
+	viper.SetDefault("OAUTH_STATE_TTL", "10m")
+
+	viper.SetDefault("DEVICE_CODE_TTL", "10m")
+	viper.SetDefault("DEVICE_POLL_INTERVAL", "5s")
+
+	viper.SetDefault("ACCOUNT_DELETION_TTL", "5m")
+
+	viper.SetDefault("KEY_REWRAP_INTERVAL", "1h")
+	viper.SetDefault("KEY_REWRAP_BATCH_SIZE", 100)
+
+	viper.SetDefault("REAPER_INTERVAL", "1m")
+	viper.SetDefault("PURGE_GRACE_PERIOD", "24h")
+
+	viper.SetDefault("PAT_IDLE_TIMEOUT", "2160h")
+
+	viper.SetDefault("SCHEDULER_LEASE_TTL", "30s")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			log.Error("Failed to read config file", zap.Error(err))
@@ -57,6 +128,18 @@ func LoadConfig(log *zap.Logger) *Conf {
 		return nil
 	}
 
+	if err := cfg.validatePublicURLs(); err != nil {
+		log.Error("Invalid public URL configuration", zap.Error(err))
+		return nil
+	}
+
+	providers, err := loadOIDCProviders(cfg.OIDCProviderNames)
+	if err != nil {
+		log.Error("Invalid OIDC provider configuration", zap.Error(err))
+		return nil
+	}
+	cfg.OIDCProviders = providers
+
 	return &cfg
 }
 
@@ -0,0 +1,83 @@
+package configs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var oidcProviderName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// OIDCProviderConf describes a generic OpenID Connect provider. Providers are listed by name in
+// OIDC_PROVIDERS and configured through OIDC_<NAME>_* variables, where NAME is the upper-cased
+// provider name with dashes replaced by underscores.
+type OIDCProviderConf struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RedirectURL  string
+}
+
+// loadOIDCProviders reads the configuration of every provider listed in OIDC_PROVIDERS.
+func loadOIDCProviders(names string) ([]OIDCProviderConf, error) {
+	var providers []OIDCProviderConf
+	seen := map[string]bool{"google": true, "github": true}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !oidcProviderName.MatchString(name) {
+			return nil, fmt.Errorf("invalid OIDC provider name %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("OIDC provider %q is already defined", name)
+		}
+		seen[name] = true
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConf{
+			Name:         name,
+			IssuerURL:    viper.GetString(prefix + "ISSUER"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(strings.ReplaceAll(viper.GetString(prefix+"SCOPES"), ",", " ")),
+			RedirectURL:  viper.GetString(prefix + "REDIRECT_URL"),
+		}
+
+		if _, err := parseAbsoluteURL(prefix+"ISSUER", provider.IssuerURL); err != nil {
+			return nil, err
+		}
+		if provider.ClientID == "" {
+			return nil, fmt.Errorf("%sCLIENT_ID is required", prefix)
+		}
+		if provider.RedirectURL != "" {
+			if _, err := parseAbsoluteURL(prefix+"REDIRECT_URL", provider.RedirectURL); err != nil {
+				return nil, err
+			}
+		}
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = []string{"openid", "profile", "email"}
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// OIDCProvider returns the configuration of the named generic OIDC provider.
+func (c *Conf) OIDCProvider(name string) (OIDCProviderConf, bool) {
+	for _, provider := range c.OIDCProviders {
+		if provider.Name == name {
+			return provider, true
+		}
+	}
+
+	return OIDCProviderConf{}, false
+}
@@ -0,0 +1,104 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	oauthCallbackPath      = "/api/v1/auth/callback/"
+	deviceVerificationPath = "/api/v1/auth/device"
+
+	// defaultPublicBaseURL is used outside release mode when PUBLIC_BASE_URL is not set.
+	defaultPublicBaseURL = "http://localhost:8080"
+)
+
+// validatePublicURLs checks PUBLIC_BASE_URL and the per-provider redirect overrides, and caches
+// the parsed base URL used to build redirect URIs and the public origin. PUBLIC_BASE_URL defaults
+// to localhost except in release mode.
+func (c *Conf) validatePublicURLs() error {
+	if c.PublicBaseURL == "" {
+		// A production deployment falling back to localhost would build redirect URIs no
+		// provider accepts, so release mode requires the URL to be set explicitly.
+		if c.GinMode == "release" {
+			return errors.New("PUBLIC_BASE_URL must be set when GIN_MODE is release")
+		}
+		c.PublicBaseURL = defaultPublicBaseURL
+	}
+
+	base, err := parseAbsoluteURL("PUBLIC_BASE_URL", c.PublicBaseURL)
+	if err != nil {
+		return err
+	}
+	if base.RawQuery != "" || base.Fragment != "" {
+		return fmt.Errorf("PUBLIC_BASE_URL must not contain a query or fragment: %q", c.PublicBaseURL)
+	}
+
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	c.publicBaseURL = base
+
+	for _, redirect := range c.OAuthAllowedRedirects {
+		if _, err := parseAbsoluteURL("OAUTH_ALLOWED_REDIRECTS", redirect); err != nil {
+			return err
+		}
+	}
+
+	for name, override := range map[string]string{
+		"GOOGLE_REDIRECT_URL": c.GoogleRedirectURL,
+		"GITHUB_REDIRECT_URL": c.GithubRedirectURL,
+	} {
+		if override == "" {
+			continue
+		}
+		if _, err := parseAbsoluteURL(name, override); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RedirectURL returns the OAuth2 redirect URI registered for the provider: the provider specific
+// override when set, otherwise the callback route under PUBLIC_BASE_URL.
+func (c *Conf) RedirectURL(provider string) string {
+	switch provider {
+	case "google":
+		if c.GoogleRedirectURL != "" {
+			return c.GoogleRedirectURL
+		}
+	case "github":
+		if c.GithubRedirectURL != "" {
+			return c.GithubRedirectURL
+		}
+	default:
+		if provider, ok := c.OIDCProvider(provider); ok && provider.RedirectURL != "" {
+			return provider.RedirectURL
+		}
+	}
+
+	return c.publicBaseURL.String() + oauthCallbackPath + provider
+}
+
+// DeviceVerificationURL returns the page where users enter the code shown by a device.
+func (c *Conf) DeviceVerificationURL() string {
+	return c.publicBaseURL.String() + deviceVerificationPath
+}
+
+// PublicOrigin returns the scheme and host of PUBLIC_BASE_URL.
+func (c *Conf) PublicOrigin() string {
+	return c.publicBaseURL.Scheme + "://" + c.publicBaseURL.Host
+}
+
+func parseAbsoluteURL(name string, raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid URL: %w", name, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%s must be an absolute http or https URL: %q", name, raw)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,42 @@
+package configs
+
+import "testing"
+
+func TestValidatePublicURLsBaseURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		ginMode      string
+		baseURL      string
+		wantErr      bool
+		wantRedirect string
+	}{
+		{
+			name:         "default outside release mode",
+			ginMode:      "debug",
+			wantRedirect: "http://localhost:8080/api/v1/auth/callback/google",
+		},
+		{name: "required in release mode", ginMode: "release", wantErr: true},
+		{
+			name:         "explicit in release mode",
+			ginMode:      "release",
+			baseURL:      "https://vault.example.com/",
+			wantRedirect: "https://vault.example.com/api/v1/auth/callback/google",
+		},
+		{name: "relative", ginMode: "release", baseURL: "vault.example.com", wantErr: true},
+		{name: "query", ginMode: "release", baseURL: "https://vault.example.com/?a=b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Conf{GinMode: tt.ginMode, PublicBaseURL: tt.baseURL}
+
+			err := cfg.validatePublicURLs()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validatePublicURLs error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.RedirectURL("google") != tt.wantRedirect {
+				t.Fatalf("RedirectURL = %q, want %q", cfg.RedirectURL("google"), tt.wantRedirect)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+CREATE TABLE sessions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider auth_provider_type NOT NULL,
+  user_agent TEXT,
+  ip_address VARCHAR(45),
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+INSERT INTO sessions (id, user_id, provider, created_at, last_seen_at, revoked_at)
+SELECT rt.family_id, rt.user_id, u.provider, MIN(rt.created_at), MAX(rt.created_at),
+       CASE WHEN bool_and(rt.revoked_at IS NOT NULL) THEN MAX(rt.revoked_at) END
+FROM refresh_tokens rt
+JOIN users u ON u.id = rt.user_id
+GROUP BY rt.family_id, rt.user_id, u.provider;
+
+ALTER TABLE refresh_tokens
+  ADD CONSTRAINT fk_refresh_tokens_session
+  FOREIGN KEY (family_id) REFERENCES sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_sessions_user ON sessions(user_id);
+CREATE INDEX idx_sessions_user_active ON sessions(user_id, last_seen_at) WHERE revoked_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE vault_rooms DROP COLUMN IF EXISTS wrapped_key;
@@ -0,0 +1 @@
+ALTER TABLE vault_rooms ADD COLUMN wrapped_key BYTEA;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_vault_rooms_key_version;
+
+ALTER TABLE vault_rooms DROP COLUMN IF EXISTS key_version;
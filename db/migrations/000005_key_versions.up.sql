@@ -0,0 +1,3 @@
+ALTER TABLE vault_rooms ADD COLUMN key_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_vault_rooms_key_version ON vault_rooms(key_version) WHERE wrapped_key IS NOT NULL;
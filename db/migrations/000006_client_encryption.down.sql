@@ -0,0 +1,5 @@
+ALTER TABLE secret_items
+  DROP COLUMN IF EXISTS algorithm,
+  DROP COLUMN IF EXISTS encryption_mode;
+
+DROP TYPE IF EXISTS encryption_mode_type;
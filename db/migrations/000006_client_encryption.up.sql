@@ -0,0 +1,5 @@
+CREATE TYPE encryption_mode_type AS ENUM ('server', 'client');
+
+ALTER TABLE secret_items
+  ADD COLUMN encryption_mode encryption_mode_type NOT NULL DEFAULT 'server',
+  ADD COLUMN algorithm VARCHAR(32) NOT NULL DEFAULT 'AES-256-GCM';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_secret_items_expires;
+
+ALTER TABLE secret_items
+  DROP CONSTRAINT IF EXISTS valid_remaining_views,
+  DROP CONSTRAINT IF EXISTS valid_max_views,
+  DROP COLUMN IF EXISTS remaining_views,
+  DROP COLUMN IF EXISTS max_views,
+  DROP COLUMN IF EXISTS expires_at;
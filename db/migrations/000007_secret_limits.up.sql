@@ -0,0 +1,10 @@
+ALTER TABLE secret_items
+  ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN max_views INTEGER NOT NULL DEFAULT 1,
+  ADD COLUMN remaining_views INTEGER NOT NULL DEFAULT 1,
+  ADD CONSTRAINT valid_max_views CHECK (max_views >= 1),
+  ADD CONSTRAINT valid_remaining_views CHECK (remaining_views >= 0 AND remaining_views <= max_views);
+
+UPDATE secret_items SET remaining_views = 0 WHERE is_burned = true;
+
+CREATE INDEX idx_secret_items_expires ON secret_items(expires_at) WHERE expires_at IS NOT NULL;
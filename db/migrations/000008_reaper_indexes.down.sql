@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_secret_items_burned_at;
@@ -0,0 +1 @@
+CREATE INDEX idx_secret_items_burned_at ON secret_items(burned_at) WHERE is_burned = true;
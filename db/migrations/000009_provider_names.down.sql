@@ -0,0 +1,7 @@
+CREATE TYPE auth_provider_type AS ENUM ('google', 'github');
+
+DELETE FROM sessions WHERE provider NOT IN ('google', 'github');
+DELETE FROM users WHERE provider NOT IN ('google', 'github');
+
+ALTER TABLE sessions ALTER COLUMN provider TYPE auth_provider_type USING provider::auth_provider_type;
+ALTER TABLE users ALTER COLUMN provider TYPE auth_provider_type USING provider::auth_provider_type;
@@ -0,0 +1,4 @@
+ALTER TABLE users ALTER COLUMN provider TYPE VARCHAR(64) USING provider::text;
+ALTER TABLE sessions ALTER COLUMN provider TYPE VARCHAR(64) USING provider::text;
+
+DROP TYPE auth_provider_type;
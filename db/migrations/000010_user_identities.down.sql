@@ -0,0 +1,24 @@
+ALTER TABLE users
+  ADD COLUMN provider VARCHAR(64),
+  ADD COLUMN provider_id VARCHAR(255);
+
+UPDATE users u
+SET provider = i.provider, provider_id = i.provider_id
+FROM (
+  SELECT DISTINCT ON (user_id) user_id, provider, provider_id
+  FROM user_identities
+  ORDER BY user_id, created_at, id
+) i
+WHERE i.user_id = u.id;
+
+DELETE FROM users WHERE provider IS NULL;
+
+ALTER TABLE users
+  ALTER COLUMN provider SET NOT NULL,
+  ALTER COLUMN provider_id SET NOT NULL,
+  ADD CONSTRAINT unique_provider_user UNIQUE (provider, provider_id),
+  ADD CONSTRAINT unique_email_per_provider UNIQUE (email, provider);
+
+CREATE INDEX idx_users_provider ON users(provider, provider_id);
+
+DROP TABLE IF EXISTS user_identities;
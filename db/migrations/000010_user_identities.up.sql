@@ -0,0 +1,25 @@
+CREATE TABLE user_identities (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(64) NOT NULL,
+  provider_id VARCHAR(255) NOT NULL,
+  email VARCHAR(255),
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT unique_provider_identity UNIQUE (provider, provider_id),
+  CONSTRAINT unique_user_provider UNIQUE (user_id, provider)
+);
+
+INSERT INTO user_identities (user_id, provider, provider_id, email, created_at)
+SELECT id, provider, provider_id, email, created_at
+FROM users;
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+
+DROP INDEX IF EXISTS idx_users_provider;
+
+ALTER TABLE users
+  DROP CONSTRAINT IF EXISTS unique_email_per_provider,
+  DROP CONSTRAINT IF EXISTS unique_provider_user,
+  DROP COLUMN provider,
+  DROP COLUMN provider_id;
@@ -0,0 +1,3 @@
+ALTER TABLE user_identities DROP COLUMN IF EXISTS email_verified;
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE user_identities ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,17 @@
+CREATE TABLE personal_access_tokens (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  token_hash BYTEA NOT NULL,
+  scopes TEXT[] NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE,
+  last_used_at TIMESTAMP WITH TIME ZONE,
+  revoked_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT unique_personal_access_token_hash UNIQUE (token_hash),
+  CONSTRAINT personal_access_token_name_length CHECK (length(name) >= 1),
+  CONSTRAINT personal_access_token_scopes CHECK (cardinality(scopes) > 0)
+);
+
+CREATE INDEX idx_personal_access_tokens_user ON personal_access_tokens(user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,5 @@
+CREATE TABLE job_fences (
+  name VARCHAR(64) PRIMARY KEY,
+  token BIGINT NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
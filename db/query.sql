@@ -1,15 +1,78 @@
--- name: GetUserByProvider :one
+-- name: GetUserByIdentity :one
+SELECT u.* FROM users u
+JOIN user_identities i ON i.user_id = u.id
+WHERE i.provider = $1 AND i.provider_id = $2 LIMIT 1;
+
+-- name: CreateUser :one
+INSERT INTO users (email, email_verified)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: SetVerifiedUserEmail :exec
+UPDATE users
+SET email = $2, email_verified = true
+WHERE id = $1 AND (email IS NULL OR NOT email_verified);
+
+-- name: GetUser :one
 SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: CountUserRooms :one
+SELECT
+  (SELECT count(*) FROM vault_rooms r WHERE r.owner_id = $1) AS owned_rooms,
+  (SELECT count(*) FROM room_members m
+   JOIN vault_rooms r ON r.id = m.room_id
+   WHERE m.user_id = $1 AND r.owner_id <> $1) AS joined_rooms;
+
+-- name: TransferOwnedRooms :execrows
+WITH successors AS (
+  SELECT DISTINCT ON (m.room_id) m.room_id, m.user_id
+  FROM room_members m
+  JOIN vault_rooms r ON r.id = m.room_id
+  WHERE r.owner_id = $1 AND m.user_id <> $1
+  ORDER BY m.room_id, m.role, m.created_at
+), transferred AS (
+  UPDATE vault_rooms r
+  SET owner_id = s.user_id
+  FROM successors s
+  WHERE r.id = s.room_id
+  RETURNING r.id, r.owner_id
+)
+UPDATE room_members m
+SET role = 'admin'
+FROM transferred t
+WHERE m.room_id = t.id AND m.user_id = t.owner_id;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
 WHERE provider = $1 AND provider_id = $2 LIMIT 1;
 
--- name: CreateUser :one
-INSERT INTO users (email, provider, provider_id)
-VALUES ($1, $2, $3)
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, provider_id, email, email_verified)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: UpdateUserIdentityEmail :exec
+UPDATE user_identities
+SET email = $3, email_verified = $4
+WHERE provider = $1 AND provider_id = $2;
+
+-- name: ListUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2;
+
 -- name: CreateRoom :one
-INSERT INTO vault_rooms (owner_id, name, access_code, expires_at)
-VALUES ($1, $2, $3, $4)
+INSERT INTO vault_rooms (id, owner_id, name, access_code, expires_at, wrapped_key, key_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: AddMemberToRoom :one
@@ -22,30 +85,165 @@ SELECT r.* FROM vault_rooms r
 JOIN room_members m ON r.id = m.room_id
 WHERE m.user_id = $1 AND r.is_active = true;
 
--- name: DeleteRoom :exec
+-- name: DeleteRoom :execrows
 DELETE FROM vault_rooms
 WHERE id = $1 AND owner_id = $2;
 
 -- name: CreateSecret :one
-INSERT INTO secret_items (room_id, creator_id, encrypted_content, nonce)
-VALUES ($1, $2, $3, $4)
+INSERT INTO secret_items (
+  id, room_id, creator_id, encrypted_content, nonce, encryption_mode, algorithm,
+  expires_at, max_views, remaining_views
+)
+SELECT $1, r.id, $3, $4, $5, $6, $7, $8, $9, $9
+FROM vault_rooms r
+WHERE r.id = $2 AND r.is_active = true
+  AND (r.expires_at IS NULL OR r.expires_at > CURRENT_TIMESTAMP)
 RETURNING *;
 
 -- name: ListSecretsByRoom :many
 SELECT id, creator_id, created_at, is_burned 
 FROM secret_items
-WHERE room_id = $1 AND is_burned = false;
+WHERE room_id = $1 AND is_burned = false
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP);
 
--- name: GetSecretForView :one
-SELECT * FROM secret_items
-WHERE id = $1 AND room_id = $2 AND is_burned = false 
-LIMIT 1;
+-- name: GetMemberRole :one
+SELECT role FROM room_members
+WHERE room_id = $1 AND user_id = $2;
 
--- name: BurnSecret :exec
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UseRefreshToken :one
+UPDATE refresh_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetRefreshTokenByHash :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, provider, user_agent, ip_address)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP, user_agent = $2, ip_address = $3
+WHERE id = $1;
+
+-- name: ListActiveSessionsByUser :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY last_seen_at DESC;
+
+-- name: RevokeSession :one
+UPDATE sessions
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING id;
+
+-- name: RevokeAllSessionsByUser :many
+UPDATE sessions
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE user_id = sqlc.arg(user_id)
+  AND (revoked_at IS NULL OR revoked_at > sqlc.arg(revoked_after))
+RETURNING id;
+
+-- name: RevealSecret :one
+UPDATE secret_items s
+SET remaining_views = s.remaining_views - 1,
+    is_burned = s.remaining_views <= 1,
+    burned_at = CASE WHEN s.remaining_views <= 1 THEN CURRENT_TIMESTAMP ELSE s.burned_at END
+FROM vault_rooms r
+WHERE s.id = $1 AND s.room_id = $2 AND s.is_burned = false
+  AND (s.expires_at IS NULL OR s.expires_at > CURRENT_TIMESTAMP)
+  AND r.id = s.room_id AND r.is_active = true
+  AND (r.expires_at IS NULL OR r.expires_at > CURRENT_TIMESTAMP)
+RETURNING s.*;
+
+-- name: GetRoomKey :one
+SELECT wrapped_key, key_version FROM vault_rooms
+WHERE id = $1 AND is_active = true
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP);
+
+-- name: SetRoomKey :execrows
+UPDATE vault_rooms
+SET wrapped_key = $2, key_version = $3
+WHERE id = $1 AND wrapped_key IS NULL;
+
+-- name: ListRoomsForRewrap :many
+SELECT id, wrapped_key, key_version FROM vault_rooms
+WHERE wrapped_key IS NOT NULL AND key_version <> sqlc.arg(key_version) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: RewrapRoomKey :execrows
+UPDATE vault_rooms
+SET wrapped_key = sqlc.arg(wrapped_key), key_version = sqlc.arg(new_key_version)
+WHERE id = sqlc.arg(id) AND key_version = sqlc.arg(old_key_version);
+
+-- name: DeactivateExpiredRooms :execrows
+UPDATE vault_rooms
+SET is_active = false
+WHERE is_active = true AND expires_at <= CURRENT_TIMESTAMP;
+
+-- name: DeleteExpiredRooms :execrows
+DELETE FROM vault_rooms
+WHERE expires_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: BurnExpiredSecrets :execrows
 UPDATE secret_items
 SET is_burned = true, burned_at = CURRENT_TIMESTAMP
-WHERE id = $1;
+WHERE is_burned = false AND expires_at <= CURRENT_TIMESTAMP;
 
--- name: GetMemberRole :one
-SELECT role FROM room_members
-WHERE room_id = $1 AND user_id = $2;
\ No newline at end of file
+-- name: PurgeBurnedSecrets :execrows
+DELETE FROM secret_items
+WHERE is_burned = true AND burned_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: UsePersonalAccessToken :one
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+RETURNING *;
+
+-- name: PurgePersonalAccessTokens :execrows
+DELETE FROM personal_access_tokens
+WHERE revoked_at < sqlc.arg(cutoff)
+   OR expires_at < sqlc.arg(cutoff)
+   OR (expires_at IS NULL AND COALESCE(last_used_at, created_at) < sqlc.arg(idle_cutoff));
+
+-- name: AdvanceJobFence :execrows
+INSERT INTO job_fences (name, token)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET token = EXCLUDED.token, updated_at = CURRENT_TIMESTAMP
+WHERE job_fences.token <= EXCLUDED.token;
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// AccountResponseDto represents the profile of the authenticated user.
+type AccountResponseDto struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	Providers     []string  `json:"providers"`
+	OwnedRooms    int64     `json:"owned_rooms"`
+	JoinedRooms   int64     `json:"joined_rooms"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DeleteAccountRequestDto represents the payload used to delete the authenticated user's account.
+// Without a confirmation token a new one is issued; sending it back with the same room policy
+// deletes the account.
+type DeleteAccountRequestDto struct {
+	RoomPolicy        string `json:"room_policy" binding:"required,oneof=transfer delete"`
+	ConfirmationToken string `json:"confirmation_token"`
+}
+
+// DeleteAccountConfirmationDto represents the confirmation required before an account is deleted.
+type DeleteAccountConfirmationDto struct {
+	ConfirmationToken string    `json:"confirmation_token"`
+	RoomPolicy        string    `json:"room_policy"`
+	OwnedRooms        int64     `json:"owned_rooms"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
@@ -7,15 +7,39 @@ type LoginResponseDto struct {
 	URL string `json:"url"`
 }
 
-// CallbackResponseDto represents the structure of the OAuth2 callback response.
+// CallbackResponseDto represents the structure of the OAuth2 callback and token refresh responses.
 type CallbackResponseDto struct {
-	Token     string `json:"token"`
-	TokenType string `json:"token_type"`
-	ExpiryAt  int64  `json:"expiry_at"`
+	Token                string `json:"token"`
+	TokenType            string `json:"token_type"`
+	ExpiryAt             int64  `json:"expiry_at"`
+	RefreshToken         string `json:"refresh_token"`
+	RefreshTokenExpiryAt int64  `json:"refresh_token_expiry_at"`
+}
+
+// RefreshRequestDto represents the payload used to exchange a refresh token for a new token pair.
+type RefreshRequestDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 // UserInfoResponseDto holds the standardized user profile data from external auth providers.
 type UserInfoResponseDto struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// DeviceCodeResponseDto represents a device authorization response (RFC 8628). The device shows
+// the user code and verification URI, then polls the token endpoint with the device code.
+type DeviceCodeResponseDto struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceTokenRequestDto represents the payload a device polls with until the user approves it.
+type DeviceTokenRequestDto struct {
+	DeviceCode string `json:"device_code" binding:"required"`
 }
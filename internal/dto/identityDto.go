@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// IdentityResponseDto represents a provider identity the authenticated user can sign in with.
+type IdentityResponseDto struct {
+	Provider      string    `json:"provider"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// CreateRoomRequestDto represents the payload used to create a new secure room.
+type CreateRoomRequestDto struct {
+	Name       string     `json:"name" binding:"required,min=3,max=255"`
+	AccessCode string     `json:"access_code,omitempty" binding:"omitempty,min=4,max=128"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// RoomResponseDto represents the public metadata of a secure room.
+type RoomResponseDto struct {
+	ID            string     `json:"id"`
+	OwnerID       string     `json:"owner_id"`
+	Name          string     `json:"name"`
+	HasAccessCode bool       `json:"has_access_code"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
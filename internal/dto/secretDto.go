@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// SecretContentResponseDto represents a revealed secret, returned at most max_views times before it is burned.
+// Server encrypted secrets carry their plaintext in Content, while client encrypted secrets are
+// returned verbatim in EncryptedContent, Nonce and Algorithm for the client to decrypt.
+type SecretContentResponseDto struct {
+	ID               string     `json:"id"`
+	RoomID           string     `json:"room_id"`
+	CreatorID        string     `json:"creator_id"`
+	EncryptionMode   string     `json:"encryption_mode" example:"server"`
+	Content          string     `json:"content,omitempty"`
+	EncryptedContent string     `json:"encrypted_content,omitempty"`
+	Nonce            string     `json:"nonce,omitempty"`
+	Algorithm        string     `json:"algorithm,omitempty" example:"AES-256-GCM"`
+	RemainingViews   int32      `json:"remaining_views"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	BurnedAt         *time.Time `json:"burned_at,omitempty"`
+}
+
+// CreateSecretRequestDto represents the payload used to store a new secret in a room.
+// Either Content is sent in plaintext and encrypted by the server, or the client encrypts it
+// itself and sends the base64 encoded EncryptedContent and Nonce together with the Algorithm.
+// The secret burns once it has been read MaxViews times (once by default) or when ExpiresAt passes.
+type CreateSecretRequestDto struct {
+	Content          string     `json:"content" binding:"required_without=EncryptedContent,excluded_with=EncryptedContent,max=65536"`
+	EncryptedContent string     `json:"encrypted_content" binding:"omitempty,base64,max=87400"`
+	Nonce            string     `json:"nonce" binding:"required_with=EncryptedContent,omitempty,base64"`
+	Algorithm        string     `json:"algorithm" binding:"required_with=EncryptedContent,omitempty,oneof=AES-256-GCM" example:"AES-256-GCM"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	MaxViews         int32      `json:"max_views,omitempty" binding:"omitempty,min=1,max=100" example:"3"`
+}
+
+// SecretResponseDto represents the metadata of a stored secret, without its content.
+type SecretResponseDto struct {
+	ID             string     `json:"id"`
+	RoomID         string     `json:"room_id"`
+	CreatorID      string     `json:"creator_id"`
+	EncryptionMode string     `json:"encryption_mode" example:"server"`
+	Algorithm      string     `json:"algorithm" example:"AES-256-GCM"`
+	MaxViews       int32      `json:"max_views"`
+	RemainingViews int32      `json:"remaining_views"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
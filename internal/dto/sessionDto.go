@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// SessionResponseDto represents an active login session of the authenticated user.
+type SessionResponseDto struct {
+	ID         string    `json:"id"`
+	Provider   string    `json:"provider"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// CreatePersonalAccessTokenRequestDto represents the payload used to create a personal access token.
+// Tokens without ExpiresAt never expire.
+type CreatePersonalAccessTokenRequestDto struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"ci-deploy"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=rooms:read rooms:write secrets:read secrets:write" example:"rooms:read,secrets:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PersonalAccessTokenResponseDto represents a personal access token, without its secret value.
+type PersonalAccessTokenResponseDto struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatePersonalAccessTokenResponseDto represents a newly created personal access token.
+// Token is only ever returned here; it cannot be retrieved again.
+type CreatePersonalAccessTokenResponseDto struct {
+	PersonalAccessTokenResponseDto
+	Token string `json:"token" example:"vvpat_..."`
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewDeleteAccountHandler handles the self-service deletion of the authenticated user's account.
+// @Summary      Delete Current User
+// @Description  Deletes the account in two steps. Called without `confirmation_token`, it returns a short-lived token bound to the chosen room policy. Called again with that token and the same policy, it permanently deletes the account along with its identities, sessions, memberships and secrets. With `transfer`, each owned room goes to its longest standing member with the highest role; rooms without other members are deleted. With `delete`, every owned room is deleted.
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request    body      dto.DeleteAccountRequestDto  true  "Room policy and, for the second step, the confirmation token"
+// @Success      202        {object}  dto.DeleteAccountConfirmationDto "Confirmation required"
+// @Success      204        "No Content - Account deleted"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid request body"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "Invalid or expired confirmation token"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to delete account"
+// @Router       /api/v1/me [delete]
+func NewDeleteAccountHandler(
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		ctx := c.Request.Context()
+
+		var req dto.DeleteAccountRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "room_policy must be transfer or delete",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		if req.ConfirmationToken == "" {
+			counts, err := store.CountUserRooms(ctx, userID)
+			if err != nil {
+				abortDeleteFailed(c, log, err)
+				return
+			}
+
+			token, expiresAt, err := service.RequestAccountDeletion(ctx, rdb, userID, req.RoomPolicy, cfg.AccountDeletionTTL)
+			if err != nil {
+				abortDeleteFailed(c, log, err)
+				return
+			}
+
+			c.JSON(http.StatusAccepted, dto.DeleteAccountConfirmationDto{
+				ConfirmationToken: token,
+				RoomPolicy:        req.RoomPolicy,
+				OwnedRooms:        counts.OwnedRooms,
+				ExpiresAt:         expiresAt,
+			})
+			return
+		}
+
+		err := service.ConfirmAccountDeletion(ctx, rdb, userID, req.ConfirmationToken, req.RoomPolicy)
+		if errors.Is(err, service.ErrDeletionNotConfirmed) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+				Code:    http.StatusForbidden,
+				Message: "Invalid or expired confirmation token",
+				Status:  http.StatusText(http.StatusForbidden),
+			})
+			return
+		}
+		if err != nil {
+			abortDeleteFailed(c, log, err)
+			return
+		}
+
+		result, err := service.DeleteAccount(ctx, store, rdb, cfg, userID, req.RoomPolicy)
+		if err != nil {
+			abortDeleteFailed(c, log, err)
+			return
+		}
+
+		log.Info("Account deleted",
+			zap.String("user_id", userID.String()),
+			zap.String("room_policy", req.RoomPolicy),
+			zap.Int64("rooms_transferred", result.RoomsTransferred),
+			zap.Int64("rooms_deleted", result.RoomsDeleted),
+		)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func abortDeleteFailed(c *gin.Context, log *zap.Logger, err error) {
+	log.Error("Failed to delete account", zap.Error(err))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to delete account",
+		Status:  http.StatusText(http.StatusInternalServerError),
+	})
+}
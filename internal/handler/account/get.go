@@ -0,0 +1,82 @@
+// Package account contains handlers for the authenticated user's own account.
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// NewGetAccountHandler handles fetching the profile of the authenticated user.
+// @Summary      Get Current User
+// @Description  Returns the account the access token belongs to: its email, linked providers and room counts.
+// @Tags         Account
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200        {object}  dto.AccountResponseDto
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      404        {object}  dto.ErrorResponseDto "User not found"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to load account"
+// @Router       /api/v1/me [get]
+func NewGetAccountHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		ctx := c.Request.Context()
+
+		user, err := repo.GetUser(ctx, userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+		if err != nil {
+			abortLoadFailed(c, log, err)
+			return
+		}
+
+		identities, err := repo.ListUserIdentities(ctx, userID)
+		if err != nil {
+			abortLoadFailed(c, log, err)
+			return
+		}
+
+		counts, err := repo.CountUserRooms(ctx, userID)
+		if err != nil {
+			abortLoadFailed(c, log, err)
+			return
+		}
+
+		providers := make([]string, 0, len(identities))
+		for _, identity := range identities {
+			providers = append(providers, identity.Provider)
+		}
+
+		c.JSON(http.StatusOK, dto.AccountResponseDto{
+			ID:            user.ID.String(),
+			Email:         user.Email.String,
+			EmailVerified: user.EmailVerified,
+			Providers:     providers,
+			OwnedRooms:    counts.OwnedRooms,
+			JoinedRooms:   counts.JoinedRooms,
+			CreatedAt:     user.CreatedAt.Time,
+		})
+	}
+}
+
+func abortLoadFailed(c *gin.Context, log *zap.Logger, err error) {
+	log.Error("Failed to load account", zap.Error(err))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to load account",
+		Status:  http.StatusText(http.StatusInternalServerError),
+	})
+}
@@ -1,33 +1,39 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // NewCallbackHandler handles the OAuth2 callback.
 // @Summary      OAuth2 Callback
-// @Description  Exchanges authorization code for a VanishVault JWT access token.
+// @Description  Exchanges authorization code for a VanishVault JWT access token and refresh token, proving possession of the PKCE code verifier and checking the OIDC nonce.
 // @Tags         Auth
 // @Produce      json
-// @Param        provider   path      string  true  "google or github"
+// @Param        provider   path      string  true  "google, github or a configured OIDC provider name"
 // @Param        code       query     string  true  "Authorization code"
-// @Param        state      query     string  true  "CSRF state"
+// @Param        state      query     string  true  "Single-use state issued by the login endpoint"
 // @Success      200        {object}  dto.CallbackResponseDto
-// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized or invalid state"
+// @Success      302        {string}  string  "Redirect to the login redirect with the tokens in the URL fragment"
+// @Success      200        {string}  string  "Device approved page, when the login approves a device authorization request"
+// @Success      200        {string}  string  "Link confirmation page, when the login links a provider to an existing user"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized, invalid state or nonce"
 // @Failure      500        {object}  dto.ErrorResponseDto "Failed to process authentication"
 // @Router       /api/v1/auth/callback/{provider} [get]
 func NewCallbackHandler(
-	repo repository.Querier,
+	store repository.Store,
+	rdb *redis.Client,
 	cfg *configs.Conf,
+	providers *service.IdentityProviders,
 	log *zap.Logger,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -35,9 +41,18 @@ func NewCallbackHandler(
 		state := c.Query("state")
 		code := c.Query("code")
 
-		cookieState, err := c.Cookie("oauth_state")
-		if err != nil || state != cookieState {
-			log.Warn("Invalid state parameter", zap.Error(err))
+		flow, err := service.ConsumeAuthFlow(c.Request.Context(), rdb, state)
+		if err != nil && !errors.Is(err, service.ErrAuthFlowNotFound) {
+			log.Error("Failed to load authorization request", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to process authentication",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+		if err != nil || flow.Provider != provider {
+			log.Warn("Invalid state parameter", zap.String("provider", provider), zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponseDto{
 				Code:    http.StatusUnauthorized,
 				Message: "Invalid state parameter",
@@ -46,10 +61,8 @@ func NewCallbackHandler(
 			return
 		}
 
-		c.SetCookie("oauth_state", "", -1, "/", "", true, true)
-
-		oauthConfig := service.GetOauthConfig(provider, cfg)
-		if oauthConfig == nil {
+		identityProvider, ok := providers.Get(provider)
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
 				Code:    http.StatusBadRequest,
 				Message: "Invalid provider",
@@ -58,7 +71,7 @@ func NewCallbackHandler(
 			return
 		}
 
-		token, err := oauthConfig.Exchange(c.Request.Context(), code)
+		token, err := identityProvider.Exchange(c.Request.Context(), code, flow)
 		if err != nil {
 			log.Error("Failed to exchange token", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
@@ -69,7 +82,16 @@ func NewCallbackHandler(
 			return
 		}
 
-		userInfo, err := service.FetchUserInfo(provider, token.AccessToken, log)
+		userInfo, err := identityProvider.FetchIdentity(c.Request.Context(), token, flow)
+		if errors.Is(err, service.ErrNonceMismatch) {
+			log.Warn("ID token nonce mismatch", zap.String("provider", provider))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponseDto{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid ID token nonce",
+				Status:  http.StatusText(http.StatusUnauthorized),
+			})
+			return
+		}
 		if err != nil {
 			log.Error("Failed to fetch user info", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
@@ -80,39 +102,59 @@ func NewCallbackHandler(
 			return
 		}
 
-		dbProvider := repository.AuthProviderType(provider)
-
-		user, err := repo.GetUserByProvider(c, repository.GetUserByProviderParams{
-			Provider:   dbProvider,
-			ProviderID: userInfo.ID,
-		})
+		if flow.LinkUser != uuid.Nil {
+			renderLinkConfirmation(c, store, rdb, cfg, &service.PendingLink{
+				UserID:   flow.LinkUser,
+				Provider: provider,
+				Identity: *userInfo,
+			}, log)
+			return
+		}
 
+		user, err := service.ResolveUser(c.Request.Context(), store, provider, userInfo)
 		if err != nil {
-			log.Info("User not found, creating new user", zap.String("email", userInfo.Email))
-
-			user, err = repo.CreateUser(c, repository.CreateUserParams{
-				Email: pgtype.Text{
-					String: userInfo.Email,
-					Valid:  userInfo.Email != "",
-				},
-				Provider:   dbProvider,
-				ProviderID: userInfo.ID,
+			log.Error("Failed to resolve user account", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create user account",
+				Status:  http.StatusText(http.StatusInternalServerError),
 			})
+			return
+		}
 
+		if flow.UserCode != "" {
+			err := service.ApproveDeviceAuthorization(c.Request.Context(), rdb, flow.UserCode, user.ID, provider)
+			if errors.Is(err, service.ErrDeviceCodeNotFound) {
+				renderDevicePage(c, http.StatusBadRequest, devicePageData{
+					Message: "The code is invalid or has expired. Start the login again on your device.",
+				})
+				return
+			}
 			if err != nil {
-				log.Error("Failed to create user in db", zap.Error(err))
+				log.Error("Failed to approve device authorization", zap.Error(err))
 				c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
 					Code:    http.StatusInternalServerError,
-					Message: "Failed to create user account",
+					Message: "Failed to process authentication",
 					Status:  http.StatusText(http.StatusInternalServerError),
 				})
 				return
 			}
+
+			log.Info("Device authorization approved", zap.String("user_id", user.ID.String()))
+			renderDevicePage(c, http.StatusOK, devicePageData{
+				Message: "Device approved. You can close this window and return to your device.",
+			})
+			return
 		}
 
-		jwtToken, err := service.GenerateToken(user.ID, cfg)
+		tokens, err := startSession(c.Request.Context(), store, cfg, repository.CreateSessionParams{
+			UserID:    user.ID,
+			Provider:  provider,
+			UserAgent: clientUserAgent(c),
+			IpAddress: clientIP(c),
+		})
 		if err != nil {
-			log.Error("Failed to generate JWT", zap.Error(err))
+			log.Error("Failed to start session", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
 				Code:    http.StatusInternalServerError,
 				Message: "Failed to generate session token",
@@ -121,10 +163,11 @@ func NewCallbackHandler(
 			return
 		}
 
-		c.JSON(http.StatusOK, dto.CallbackResponseDto{
-			Token:     jwtToken,
-			TokenType: "Bearer",
-			ExpiryAt:  time.Now().Add(time.Hour * time.Duration(cfg.JWTExpirationHours)).Unix(),
-		})
+		if flow.Redirect != "" {
+			c.Redirect(http.StatusFound, redirectWithTokens(flow.Redirect, tokens))
+			return
+		}
+
+		c.JSON(http.StatusOK, tokens)
 	}
 }
@@ -0,0 +1,476 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// fakeStore keeps users, identities, sessions and refresh tokens in memory. Queries the callback is not
+// expected to run panic through the nil embedded Store.
+type fakeStore struct {
+	repository.Store
+
+	mu              sync.Mutex
+	users           map[uuid.UUID]repository.User
+	identities      map[string]repository.User
+	sessions        int
+	refresh         int
+	refreshTokens   map[string]repository.RefreshToken
+	revokedSessions map[uuid.UUID]bool
+	refreshErr      error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users:           map[uuid.UUID]repository.User{},
+		identities:      map[string]repository.User{},
+		refreshTokens:   map[string]repository.RefreshToken{},
+		revokedSessions: map[uuid.UUID]bool{},
+	}
+}
+
+func (s *fakeStore) ExecTx(_ context.Context, fn func(repository.Querier) error) error {
+	return fn(s)
+}
+
+func (s *fakeStore) GetUserByIdentity(_ context.Context, arg repository.GetUserByIdentityParams) (repository.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.identities[arg.Provider+":"+arg.ProviderID]
+	if !ok {
+		return repository.User{}, pgx.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (s *fakeStore) GetUser(_ context.Context, id uuid.UUID) (repository.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return repository.User{}, pgx.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (s *fakeStore) GetUserIdentity(
+	_ context.Context,
+	arg repository.GetUserIdentityParams,
+) (repository.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.identities[arg.Provider+":"+arg.ProviderID]
+	if !ok {
+		return repository.UserIdentity{}, pgx.ErrNoRows
+	}
+
+	return repository.UserIdentity{UserID: user.ID, Provider: arg.Provider, ProviderID: arg.ProviderID}, nil
+}
+
+func (s *fakeStore) CreateUser(_ context.Context, arg repository.CreateUserParams) (repository.User, error) {
+	return repository.User{ID: uuid.New(), Email: arg.Email, EmailVerified: arg.EmailVerified}, nil
+}
+
+func (s *fakeStore) CreateUserIdentity(
+	_ context.Context,
+	arg repository.CreateUserIdentityParams,
+) (repository.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.identities[arg.Provider+":"+arg.ProviderID] = repository.User{
+		ID:            arg.UserID,
+		Email:         arg.Email,
+		EmailVerified: arg.EmailVerified,
+	}
+
+	return repository.UserIdentity{
+		ID:         uuid.New(),
+		UserID:     arg.UserID,
+		Provider:   arg.Provider,
+		ProviderID: arg.ProviderID,
+		Email:      arg.Email,
+	}, nil
+}
+
+func (s *fakeStore) CreateSession(_ context.Context, arg repository.CreateSessionParams) (repository.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions++
+	return repository.Session{ID: uuid.New(), UserID: arg.UserID, Provider: arg.Provider}, nil
+}
+
+func (s *fakeStore) CreateRefreshToken(
+	_ context.Context,
+	arg repository.CreateRefreshTokenParams,
+) (repository.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refreshErr != nil {
+		return repository.RefreshToken{}, s.refreshErr
+	}
+
+	s.refresh++
+	token := repository.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		FamilyID:  arg.FamilyID,
+		TokenHash: arg.TokenHash,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	s.refreshTokens[string(arg.TokenHash)] = token
+
+	return token, nil
+}
+
+func (s *fakeStore) UseRefreshToken(_ context.Context, tokenHash []byte) (repository.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[string(tokenHash)]
+	if !ok || token.UsedAt.Valid || token.RevokedAt.Valid || !token.ExpiresAt.After(time.Now()) {
+		return repository.RefreshToken{}, pgx.ErrNoRows
+	}
+
+	token.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	s.refreshTokens[string(tokenHash)] = token
+
+	return token, nil
+}
+
+func (s *fakeStore) GetRefreshTokenByHash(_ context.Context, tokenHash []byte) (repository.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[string(tokenHash)]
+	if !ok {
+		return repository.RefreshToken{}, pgx.ErrNoRows
+	}
+
+	return token, nil
+}
+
+func (s *fakeStore) RevokeRefreshTokenFamily(_ context.Context, familyID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, token := range s.refreshTokens {
+		if token.FamilyID == familyID && !token.RevokedAt.Valid {
+			token.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			s.refreshTokens[hash] = token
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeStore) TouchSession(context.Context, repository.TouchSessionParams) error {
+	return nil
+}
+
+func (s *fakeStore) RevokeSession(_ context.Context, arg repository.RevokeSessionParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revokedSessions[arg.ID] = true
+	return arg.ID, nil
+}
+
+// stubExchangeProvider is a provider whose code exchange always succeeds, so the identity is
+// fetched from the test server the embedded provider points at.
+type stubExchangeProvider struct {
+	service.IdentityProvider
+}
+
+func (p stubExchangeProvider) Exchange(context.Context, string, *service.AuthFlow) (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "provider-access-token", TokenType: "Bearer"}, nil
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return rdb
+}
+
+func newTestConfig() *configs.Conf {
+	return &configs.Conf{
+		GoogleRedirectURL:           "http://localhost:8080/api/v1/auth/callback/google",
+		GithubRedirectURL:           "http://localhost:8080/api/v1/auth/callback/github",
+		JWTSecret:                   "test-secret",
+		JWTExpirationHours:          1,
+		RefreshTokenExpirationHours: 24,
+		OAuthStateTTL:               10 * time.Minute,
+	}
+}
+
+// startFlow stores a fresh authorization request for the provider, as the login endpoint does.
+func startFlow(t *testing.T, rdb *redis.Client, cfg *configs.Conf, provider string) *service.AuthFlow {
+	t.Helper()
+
+	flow, err := service.NewAuthFlow(provider, "", cfg.OAuthStateTTL)
+	if err != nil {
+		t.Fatalf("NewAuthFlow: %v", err)
+	}
+	if err := service.SaveAuthFlow(context.Background(), rdb, flow); err != nil {
+		t.Fatalf("SaveAuthFlow: %v", err)
+	}
+
+	return flow
+}
+
+// callback sends the provider's redirect back to the callback handler and returns the response.
+func callback(
+	t *testing.T,
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	providers *service.IdentityProviders,
+	provider string,
+	state string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/auth/callback/:provider", NewCallbackHandler(store, rdb, cfg, providers, zap.NewNop()))
+
+	query := url.Values{"code": {"authorization-code"}, "state": {state}}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/callback/"+provider+"?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) dto.ErrorResponseDto {
+	t.Helper()
+
+	var res dto.ErrorResponseDto
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode error response %q: %v", w.Body.String(), err)
+	}
+
+	return res
+}
+
+func TestCallbackFetchIdentity(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantStatus  int
+		wantMessage string
+		wantSession bool
+	}{
+		{
+			name:        "success",
+			status:      http.StatusOK,
+			body:        `{"id":"42","email":"user@example.com","verified_email":true}`,
+			wantStatus:  http.StatusOK,
+			wantSession: true,
+		},
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			body:        `{"error":"invalid_token"}`,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Failed to fetch user info",
+		},
+		{
+			name:        "server error with valid json",
+			status:      http.StatusInternalServerError,
+			body:        `{"id":"42","email":"user@example.com"}`,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Failed to fetch user info",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userInfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer provider-access-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer userInfo.Close()
+
+			cfg := newTestConfig()
+			google := service.NewGoogleProvider(cfg)
+			google.UserInfoURL = userInfo.URL
+
+			providers := service.NewIdentityProviders(cfg)
+			providers.Register(stubExchangeProvider{google})
+
+			store := newFakeStore()
+			rdb := newTestRedis(t)
+			flow := startFlow(t, rdb, cfg, "google")
+
+			w := callback(t, store, rdb, cfg, providers, "google", flow.State)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantMessage != "" {
+				if res := decodeError(t, w); res.Message != tt.wantMessage {
+					t.Fatalf("message = %q, want %q", res.Message, tt.wantMessage)
+				}
+			}
+			if created := store.sessions == 1 && store.refresh == 1; created != tt.wantSession {
+				t.Fatalf("session created = %v, want %v", created, tt.wantSession)
+			}
+			if tt.wantSession {
+				var res dto.CallbackResponseDto
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("decode tokens: %v", err)
+				}
+				if res.Token == "" || res.RefreshToken == "" {
+					t.Fatalf("missing tokens in %s", w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackGithubEmails(t *testing.T) {
+	tests := []struct {
+		name         string
+		profileEmail string
+		emails       string
+		wantEmail    string
+		wantVerified bool
+	}{
+		{
+			name:         "private profile email",
+			emails:       `[{"email":"old@example.com","verified":true},{"email":"me@example.com","primary":true,"verified":true}]`,
+			wantEmail:    "me@example.com",
+			wantVerified: true,
+		},
+		{
+			name:         "verified secondary over unverified primary",
+			profileEmail: "public@example.com",
+			emails:       `[{"email":"new@example.com","primary":true},{"email":"work@example.com","verified":true}]`,
+			wantEmail:    "work@example.com",
+			wantVerified: true,
+		},
+		{
+			name:         "no usable email falls back to profile",
+			profileEmail: "public@example.com",
+			emails:       `[{"email":"other@example.com"}]`,
+			wantEmail:    "public@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := http.NewServeMux()
+			api.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": 7, "login": "octocat", "email": tt.profileEmail})
+			})
+			api.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer provider-access-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.emails))
+			})
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			cfg := newTestConfig()
+			github := service.NewGithubProvider(cfg)
+			github.UserURL = server.URL + "/user"
+			github.EmailsURL = server.URL + "/user/emails"
+
+			providers := service.NewIdentityProviders(cfg)
+			providers.Register(stubExchangeProvider{github})
+
+			store := newFakeStore()
+			rdb := newTestRedis(t)
+			flow := startFlow(t, rdb, cfg, "github")
+
+			w := callback(t, store, rdb, cfg, providers, "github", flow.State)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			identity, ok := store.identities["github:7"]
+			if !ok {
+				t.Fatalf("no identity created for the GitHub user")
+			}
+			if identity.Email.String != tt.wantEmail || identity.EmailVerified != tt.wantVerified {
+				t.Fatalf("identity email = %q (verified %v), want %q (verified %v)",
+					identity.Email.String, identity.EmailVerified, tt.wantEmail, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestCallbackRejectsInvalidState(t *testing.T) {
+	cfg := newTestConfig()
+	providers := service.NewIdentityProviders(cfg)
+	providers.Register(stubExchangeProvider{service.NewGoogleProvider(cfg)})
+	rdb := newTestRedis(t)
+
+	tests := []struct {
+		name     string
+		provider string
+		state    func() string
+	}{
+		{name: "unknown state", provider: "google", state: func() string { return "unknown" }},
+		{name: "other provider", provider: "google", state: func() string { return startFlow(t, rdb, cfg, "github").State }},
+		{
+			name:     "replayed state",
+			provider: "google",
+			state: func() string {
+				flow := startFlow(t, rdb, cfg, "google")
+				if _, err := service.ConsumeAuthFlow(context.Background(), rdb, flow.State); err != nil {
+					t.Fatalf("ConsumeAuthFlow: %v", err)
+				}
+				return flow.State
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := callback(t, newFakeStore(), rdb, cfg, providers, tt.provider, tt.state())
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusUnauthorized, w.Body.String())
+			}
+		})
+	}
+}
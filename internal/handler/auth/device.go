@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Error codes returned by the device token endpoint, as defined by RFC 8628.
+const (
+	deviceAuthorizationPending = "authorization_pending"
+	deviceSlowDown             = "slow_down"
+	deviceExpiredToken         = "expired_token"
+)
+
+// loginPath is where the verification page sends the user code, followed by the provider name.
+const loginPath = "/api/v1/auth/login/"
+
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>VanishVault device login</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if .Providers}}
+<form method="get">
+<label for="user_code">Enter the code shown on your device</label>
+<input id="user_code" name="user_code" value="{{.UserCode}}" autocomplete="off" required>
+{{range .Providers}}<button type="submit" formaction="{{$.LoginPath}}{{.}}">Continue with {{.}}</button>
+{{end}}</form>
+{{end}}
+</body>
+</html>
+`))
+
+type devicePageData struct {
+	Message   string
+	UserCode  string
+	LoginPath string
+	Providers []string
+}
+
+// NewDeviceCodeHandler starts a device authorization request.
+// @Summary      Start Device Login
+// @Description  Starts an OAuth 2.0 device authorization request (RFC 8628) for clients without a browser, such as a terminal. The client shows the user code and verification URI, the user logs in with any provider on a second device, and the client polls the token endpoint with the device code.
+// @Tags         Auth
+// @Produce      json
+// @Success      200        {object}  dto.DeviceCodeResponseDto
+// @Failure      500        {object}  dto.ErrorResponseDto "Internal server error"
+// @Router       /api/v1/auth/device/code [post]
+func NewDeviceCodeHandler(
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceCode, auth, err := service.CreateDeviceAuthorization(c.Request.Context(), rdb, cfg.DeviceCodeTTL)
+		if err != nil {
+			log.Error("Failed to create device authorization", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Internal error when starting authentication",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		userCode := service.FormatUserCode(auth.UserCode)
+		verificationURI := cfg.DeviceVerificationURL()
+
+		c.JSON(http.StatusOK, dto.DeviceCodeResponseDto{
+			DeviceCode:              deviceCode,
+			UserCode:                userCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?" + url.Values{"user_code": {userCode}}.Encode(),
+			ExpiresIn:               int(cfg.DeviceCodeTTL.Seconds()),
+			Interval:                int(cfg.DevicePollInterval.Seconds()),
+		})
+	}
+}
+
+// NewDeviceVerificationHandler serves the page where users approve a device.
+// @Summary      Device Verification Page
+// @Description  HTML page where the user enters the code shown by the device and picks a provider to log in with. The login approves the device instead of returning tokens to the browser.
+// @Tags         Auth
+// @Produce      html
+// @Param        user_code  query     string  false "User code shown by the device"
+// @Success      200        {string}  string  "Verification page"
+// @Router       /api/v1/auth/device [get]
+func NewDeviceVerificationHandler(providers *service.IdentityProviders) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		renderDevicePage(c, http.StatusOK, devicePageData{
+			UserCode:  c.Query("user_code"),
+			LoginPath: loginPath,
+			Providers: providers.Names(),
+		})
+	}
+}
+
+// NewDeviceTokenHandler redeems an approved device authorization for a token pair.
+// @Summary      Poll Device Login
+// @Description  Polled by the device with its device code. Until the user approves the request it answers 400 with `authorization_pending`, or `slow_down` when polled faster than the advertised interval; `expired_token` means the device must start over. Once approved, the device receives a new session's Access/Refresh Token pair, exactly once.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request    body      dto.DeviceTokenRequestDto  true  "Object containing the device_code"
+// @Success      200        {object}  dto.CallbackResponseDto "Access and Refresh Tokens"
+// @Failure      400        {object}  dto.ErrorResponseDto "authorization_pending, slow_down or expired_token"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to generate session token"
+// @Router       /api/v1/auth/device/token [post]
+func NewDeviceTokenHandler(
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.DeviceTokenRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Missing device_code",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		auth, err := service.PollDeviceAuthorization(c.Request.Context(), rdb, req.DeviceCode, cfg.DevicePollInterval)
+		if err != nil {
+			var message string
+			switch {
+			case errors.Is(err, service.ErrDeviceAuthorizationPending):
+				message = deviceAuthorizationPending
+			case errors.Is(err, service.ErrDeviceSlowDown):
+				message = deviceSlowDown
+			case errors.Is(err, service.ErrDeviceCodeNotFound):
+				message = deviceExpiredToken
+			default:
+				log.Error("Failed to load device authorization", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+					Code:    http.StatusInternalServerError,
+					Message: "Failed to process authentication",
+					Status:  http.StatusText(http.StatusInternalServerError),
+				})
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: message,
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		tokens, err := startSession(c.Request.Context(), store, cfg, repository.CreateSessionParams{
+			UserID:    auth.UserID,
+			Provider:  auth.Provider,
+			UserAgent: clientUserAgent(c),
+			IpAddress: clientIP(c),
+		})
+		if err != nil {
+			log.Error("Failed to start session", zap.Error(err))
+			restoreCtx := context.WithoutCancel(c.Request.Context())
+			if err := service.RestoreDeviceAuthorization(restoreCtx, rdb, req.DeviceCode, auth); err != nil {
+				log.Error("Failed to restore device authorization", zap.Error(err))
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to generate session token",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Device login completed", zap.String("user_id", auth.UserID.String()))
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+func renderDevicePage(c *gin.Context, status int, data devicePageData) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	_ = devicePage.Execute(c.Writer, data)
+}
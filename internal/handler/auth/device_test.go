@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestDeviceTokenKeepsApprovalWhenIssuanceFails(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.DevicePollInterval = time.Second
+	store := newFakeStore()
+	rdb := newTestRedis(t)
+
+	deviceCode, auth, err := service.CreateDeviceAuthorization(ctx, rdb, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateDeviceAuthorization: %v", err)
+	}
+	if err := service.ApproveDeviceAuthorization(ctx, rdb, auth.UserCode, uuid.New(), "google"); err != nil {
+		t.Fatalf("ApproveDeviceAuthorization: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/auth/device/token", NewDeviceTokenHandler(store, rdb, cfg, zap.NewNop()))
+	poll := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.DeviceTokenRequestDto{DeviceCode: deviceCode})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/device/token", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	store.refreshErr = errors.New("database unavailable")
+	if w := poll(); w.Code != http.StatusInternalServerError {
+		t.Fatalf("failing issuance status = %d, want %d: %s", w.Code, http.StatusInternalServerError, w.Body.String())
+	}
+
+	store.refreshErr = nil
+	w := poll()
+	if w.Code != http.StatusOK {
+		t.Fatalf("retry status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var tokens dto.CallbackResponseDto
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil || tokens.Token == "" {
+		t.Fatalf("retry did not return tokens: %s", w.Body.String())
+	}
+
+	if w := poll(); w.Code != http.StatusBadRequest || decodeError(t, w).Message != deviceExpiredToken {
+		t.Fatalf("poll after redemption = %d %s, want %s", w.Code, w.Body.String(), deviceExpiredToken)
+	}
+}
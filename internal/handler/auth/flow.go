@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+)
+
+// isAllowedRedirect reports whether the post-login redirect may receive tokens: it must share
+// the origin of PUBLIC_BASE_URL or of an OAUTH_ALLOWED_REDIRECTS entry, or be a loopback URL as
+// used by native and CLI clients (RFC 8252).
+func isAllowedRedirect(cfg *configs.Conf, redirect string) bool {
+	u, err := url.Parse(redirect)
+	if err != nil || u.Host == "" || u.User != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	if u.Scheme == "http" {
+		if ip := net.ParseIP(u.Hostname()); ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+
+	origin := u.Scheme + "://" + u.Host
+	if strings.EqualFold(origin, cfg.PublicOrigin()) {
+		return true
+	}
+	for _, allowed := range cfg.OAuthAllowedRedirects {
+		if strings.EqualFold(origin, strings.TrimSuffix(allowed, "/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redirectWithTokens appends the token pair to the fragment of the redirect URL, which browsers
+// never send to the server hosting the redirect target.
+func redirectWithTokens(redirect string, tokens *dto.CallbackResponseDto) string {
+	fragment := url.Values{}
+	fragment.Set("token", tokens.Token)
+	fragment.Set("token_type", tokens.TokenType)
+	fragment.Set("expiry_at", strconv.FormatInt(tokens.ExpiryAt, 10))
+	fragment.Set("refresh_token", tokens.RefreshToken)
+	fragment.Set("refresh_token_expiry_at", strconv.FormatInt(tokens.RefreshTokenExpiryAt, 10))
+
+	u, _ := url.Parse(redirect)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String() + "#" + fragment.Encode()
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// linkConfirmPath is where the confirmation page posts the pending link token.
+const linkConfirmPath = "/api/v1/auth/link/confirm"
+
+var linkPage = template.Must(template.New("link").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>VanishVault account linking</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if .Token}}
+<p>Link the {{.Provider}} account {{.IdentityEmail}} to the VanishVault account {{.AccountEmail}}? You will be able to sign in to that account with it.</p>
+<form method="post" action="{{.ConfirmPath}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Link accounts</button>
+</form>
+<p>If you did not ask to link accounts, close this window.</p>
+{{end}}
+</body>
+</html>
+`))
+
+type linkPageData struct {
+	Message       string
+	Token         string
+	Provider      string
+	IdentityEmail string
+	AccountEmail  string
+	ConfirmPath   string
+}
+
+// renderLinkPage writes the account linking page. The page must not be framed, otherwise another
+// site could overlay it and trick the user into clicking the confirm button.
+func renderLinkPage(c *gin.Context, status int, data linkPageData) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "frame-ancestors 'none'")
+	_ = linkPage.Execute(c.Writer, data)
+}
+
+// renderLinkConfirmation stores the identity returned by the provider as a pending link and asks the
+// user to confirm it. Anyone with an access token can start a link flow and send the provider URL to
+// a victim, so linking straight from the callback would attach the victim's identity to the
+// attacker's account.
+func renderLinkConfirmation(
+	c *gin.Context,
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	link *service.PendingLink,
+	log *zap.Logger,
+) {
+	user, err := store.GetUser(c.Request.Context(), link.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		renderLinkPage(c, http.StatusBadRequest, linkPageData{
+			Message: "The account that started linking no longer exists.",
+		})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to load user for identity link", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to link identity",
+			Status:  http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	token, err := service.SavePendingLink(c.Request.Context(), rdb, link, cfg.OAuthStateTTL)
+	if err != nil {
+		log.Error("Failed to save pending identity link", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to link identity",
+			Status:  http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	renderLinkPage(c, http.StatusOK, linkPageData{
+		Token:         token,
+		Provider:      link.Provider,
+		IdentityEmail: link.Identity.Email,
+		AccountEmail:  user.Email.String,
+		ConfirmPath:   linkConfirmPath,
+	})
+}
+
+// NewConfirmLinkHandler links a pending provider identity once the user confirms it.
+// @Summary      Confirm Identity Link
+// @Description  Submitted by the confirmation page the callback shows for link flows. Links the provider identity to the account that started the flow. Each token can be used once.
+// @Tags         Auth
+// @Accept       x-www-form-urlencoded
+// @Produce      html
+// @Param        token      formData  string  true  "Pending link token from the confirmation page"
+// @Success      200        {string}  string  "Identity linked page"
+// @Failure      400        {string}  string  "Invalid or expired link page"
+// @Failure      409        {string}  string  "Identity already linked page"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to link identity"
+// @Router       /api/v1/auth/link/confirm [post]
+func NewConfirmLinkHandler(
+	store repository.Store,
+	rdb *redis.Client,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, err := service.ConsumePendingLink(c.Request.Context(), rdb, c.PostForm("token"))
+		if errors.Is(err, service.ErrPendingLinkNotFound) {
+			renderLinkPage(c, http.StatusBadRequest, linkPageData{
+				Message: "The link request is invalid or has expired. Start linking again from your account.",
+			})
+			return
+		}
+		if err != nil {
+			log.Error("Failed to load pending identity link", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to link identity",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		_, err = service.LinkIdentity(c.Request.Context(), store, link.UserID, link.Provider, &link.Identity)
+		if errors.Is(err, service.ErrIdentityLinked) || errors.Is(err, service.ErrProviderLinked) {
+			log.Warn("Rejected identity link", zap.String("provider", link.Provider), zap.Error(err))
+			renderLinkPage(c, http.StatusConflict, linkPageData{
+				Message: linkConflictMessage(err) + ".",
+			})
+			return
+		}
+		if err != nil {
+			log.Error("Failed to link identity", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to link identity",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Identity linked", zap.String("user_id", link.UserID.String()), zap.String("provider", link.Provider))
+		renderLinkPage(c, http.StatusOK, linkPageData{
+			Message: "Accounts linked. You can close this window.",
+		})
+	}
+}
+
+func linkConflictMessage(err error) string {
+	if errors.Is(err, service.ErrIdentityLinked) {
+		return "This identity is already linked to another account"
+	}
+
+	return "Another identity of this provider is already linked to your account"
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var linkTokenPattern = regexp.MustCompile(`name="token" value="([^"]+)"`)
+
+// confirmLink posts the confirmation form the link page shows and returns the response.
+func confirmLink(t *testing.T, store repository.Store, rdb *redis.Client, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST(linkConfirmPath, NewConfirmLinkHandler(store, rdb, zap.NewNop()))
+
+	form := url.Values{"token": {token}}
+	req := httptest.NewRequest(http.MethodPost, linkConfirmPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCallbackLinkRequiresConfirmation(t *testing.T) {
+	userInfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"42","email":"victim@example.com"}`))
+	}))
+	defer userInfo.Close()
+
+	cfg := newTestConfig()
+	google := service.NewGoogleProvider(cfg)
+	google.UserInfoURL = userInfo.URL
+
+	providers := service.NewIdentityProviders(cfg)
+	providers.Register(stubExchangeProvider{google})
+
+	account := repository.User{ID: uuid.New(), Email: pgtype.Text{String: "owner@example.com", Valid: true}}
+	store := newFakeStore()
+	store.users[account.ID] = account
+	rdb := newTestRedis(t)
+
+	flow := startFlow(t, rdb, cfg, "google")
+	flow.LinkUser = account.ID
+	if err := service.SaveAuthFlow(context.Background(), rdb, flow); err != nil {
+		t.Fatalf("SaveAuthFlow: %v", err)
+	}
+
+	w := callback(t, store, rdb, cfg, providers, "google", flow.State)
+	if w.Code != http.StatusOK {
+		t.Fatalf("callback status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(store.identities) != 0 || store.sessions != 0 {
+		t.Fatalf("callback linked the identity or created a session before confirmation")
+	}
+	for _, want := range []string{"victim@example.com", "owner@example.com"} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Fatalf("confirmation page does not show %q: %s", want, w.Body.String())
+		}
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Fatalf("X-Frame-Options = %q, want DENY", got)
+	}
+
+	match := linkTokenPattern.FindStringSubmatch(w.Body.String())
+	if match == nil {
+		t.Fatalf("confirmation page has no token: %s", w.Body.String())
+	}
+
+	if w := confirmLink(t, store, rdb, match[1]); w.Code != http.StatusOK {
+		t.Fatalf("confirm status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if linked, ok := store.identities["google:42"]; !ok || linked.ID != account.ID {
+		t.Fatalf("identity linked to %v, want %v", linked.ID, account.ID)
+	}
+
+	if w := confirmLink(t, store, rdb, match[1]); w.Code != http.StatusBadRequest {
+		t.Fatalf("replayed confirm status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestConfirmLink(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name       string
+		linkedTo   uuid.UUID
+		token      func(t *testing.T, rdb *redis.Client) string
+		wantStatus int
+	}{
+		{
+			name:       "unknown token",
+			token:      func(*testing.T, *redis.Client) string { return "unknown" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "identity owned by another user",
+			linkedTo:   other,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			if tt.linkedTo != uuid.Nil {
+				store.identities["google:42"] = repository.User{ID: tt.linkedTo}
+			}
+			rdb := newTestRedis(t)
+
+			token := ""
+			if tt.token != nil {
+				token = tt.token(t, rdb)
+			} else {
+				var err error
+				token, err = service.SavePendingLink(context.Background(), rdb, &service.PendingLink{
+					UserID:   owner,
+					Provider: "google",
+					Identity: dto.UserInfoResponseDto{ID: "42", Email: "user@example.com"},
+				}, newTestConfig().OAuthStateTTL)
+				if err != nil {
+					t.Fatalf("SavePendingLink: %v", err)
+				}
+			}
+
+			w := confirmLink(t, store, rdb, token)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if linked := store.identities["google:42"]; linked.ID != tt.linkedTo {
+				t.Fatalf("identity owner = %v, want %v", linked.ID, tt.linkedTo)
+			}
+		})
+	}
+}
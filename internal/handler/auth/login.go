@@ -2,33 +2,42 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
-	"golang.org/x/oauth2"
 )
 
 // NewLoginHandler initiates the OAuth2 login process.
 // @Summary      Initiate OAuth2 Login
-// @Description  Redirects to the auth provider or returns the URL based on the Accept header.
+// @Description  Redirects to the auth provider or returns the URL based on the Accept header. The request carries a PKCE (S256) code challenge and, for OIDC providers, a nonce, all kept server side and keyed by the single-use state, so the URL can be opened in any browser. When `redirect` is given, the callback sends the user there with the tokens in the URL fragment; it must be a loopback URL or share the origin of the API or an allowed redirect. When `user_code` is given, the login approves that device authorization request instead.
 // @Tags         Auth
 // @Produce      json
-// @Param        provider   path      string  true  "Auth Provider" Enums(google, github)
+// @Param        provider   path      string  true  "Auth Provider: google, github or a configured OIDC provider name"
+// @Param        redirect   query     string  false "Where to send the user with their tokens after login"
+// @Param        user_code  query     string  false "User code of the device authorization request to approve"
 // @Success      200        {object}  dto.LoginResponseDto "Returns JSON with auth URL"
 // @Success      307        {string}  string  "Temporary Redirect to Provider"
-// @Failure      400        {object}  dto.ErrorResponseDto "Invalid provider"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid provider, redirect or user code"
 // @Failure      500        {object}  dto.ErrorResponseDto "Internal server error"
+// @Failure      502        {object}  dto.ErrorResponseDto "Identity provider is unavailable"
 // @Router       /api/v1/auth/login/{provider} [get]
-func NewLoginHandler(cfg *configs.Conf, log *zap.Logger) gin.HandlerFunc {
+func NewLoginHandler(
+	cfg *configs.Conf,
+	providers *service.IdentityProviders,
+	rdb *redis.Client,
+	log *zap.Logger,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		provider := c.Param("provider")
 
-		oauthConfig := service.GetOauthConfig(provider, cfg)
-		if oauthConfig == nil {
+		identityProvider, ok := providers.Get(provider)
+		if !ok {
 			log.Warn("Attempt to log in with an invalid provider", zap.String("provider", provider))
 			c.JSON(http.StatusBadRequest, dto.ErrorResponseDto{
 				Code:    http.StatusBadRequest,
@@ -38,9 +47,44 @@ func NewLoginHandler(cfg *configs.Conf, log *zap.Logger) gin.HandlerFunc {
 			return
 		}
 
-		state, err := service.GenerateRandomState()
+		redirect := c.Query("redirect")
+		if redirect != "" && !isAllowedRedirect(cfg, redirect) {
+			log.Warn("Rejected post-login redirect", zap.String("redirect", redirect))
+			c.JSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Redirect URL is not allowed",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		userCode := service.NormalizeUserCode(c.Query("user_code"))
+		if userCode != "" {
+			if redirect != "" {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponseDto{
+					Code:    http.StatusBadRequest,
+					Message: "Redirect URL cannot be combined with a device code",
+					Status:  http.StatusText(http.StatusBadRequest),
+				})
+				return
+			}
+
+			if err := service.CheckUserCode(c.Request.Context(), rdb, userCode); err != nil {
+				if !errors.Is(err, service.ErrDeviceCodeNotFound) {
+					log.Error("Failed to load device authorization", zap.Error(err))
+				}
+				renderDevicePage(c, http.StatusBadRequest, devicePageData{
+					Message:   "The code is invalid or has expired.",
+					LoginPath: loginPath,
+					Providers: providers.Names(),
+				})
+				return
+			}
+		}
+
+		flow, err := service.NewAuthFlow(provider, redirect, cfg.OAuthStateTTL)
 		if err != nil {
-			log.Error("Failed to generate random state", zap.Error(err))
+			log.Error("Failed to generate authorization request secrets", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponseDto{
 				Code:    http.StatusInternalServerError,
 				Message: "Internal error when starting authentication",
@@ -48,10 +92,28 @@ func NewLoginHandler(cfg *configs.Conf, log *zap.Logger) gin.HandlerFunc {
 			})
 			return
 		}
+		flow.UserCode = userCode
 
-		c.SetCookie("oauth_state", state, 900, "/", "", true, true)
+		url, err := identityProvider.AuthCodeURL(c.Request.Context(), flow)
+		if err != nil {
+			log.Error("Failed to build provider authorization URL", zap.String("provider", provider), zap.Error(err))
+			c.JSON(http.StatusBadGateway, dto.ErrorResponseDto{
+				Code:    http.StatusBadGateway,
+				Message: "Identity provider is unavailable",
+				Status:  http.StatusText(http.StatusBadGateway),
+			})
+			return
+		}
 
-		url := oauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+		if err := service.SaveAuthFlow(c.Request.Context(), rdb, flow); err != nil {
+			log.Error("Failed to store authorization request", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Internal error when starting authentication",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
 
 		if c.GetHeader("Accept") != "application/json" {
 			log.Info("Redirecting user to provider", zap.String("provider", provider))
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewLogoutHandler handles the logout process.
+// @Summary      Logout
+// @Description  Revokes the Access Token used in the request together with its session and Refresh Tokens.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Success      204        "No Content - Tokens revoked"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to revoke tokens"
+// @Router       /api/v1/auth/logout [post]
+func NewLogoutHandler(
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _ := middleware.GetClaims(c)
+		ctx := c.Request.Context()
+
+		if err := service.RevokeToken(ctx, rdb, claims.ID, claims.ExpiresAt); err != nil {
+			log.Error("Failed to revoke access token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to revoke tokens",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		if _, err := service.RevokeSession(ctx, repo, rdb, cfg, claims.UserID, claims.SessionID); err != nil {
+			log.Error("Failed to revoke session", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to revoke tokens",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("User logged out", zap.String("user_id", claims.UserID.String()))
+		c.Status(http.StatusNoContent)
+	}
+}
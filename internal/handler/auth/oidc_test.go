@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testIssuerClientID = "vanish-vault"
+	testIssuerKeyID    = "test-key"
+)
+
+// fakeIssuer is a minimal OpenID Connect provider. Its token endpoint enforces PKCE against the
+// challenge of the last authorization request and returns an ID token carrying the given nonce.
+type fakeIssuer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	issuer := &fakeIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testIssuerKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", issuer.token)
+
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+
+	return issuer
+}
+
+// authorize records the PKCE challenge of an authorization request, as the provider would when
+// the user is sent to the URL returned by AuthCodeURL.
+func (i *fakeIssuer) authorize(t *testing.T, authURL string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization URL: %v", err)
+	}
+	if method := u.Query().Get("code_challenge_method"); method != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", method)
+	}
+
+	i.challenge = u.Query().Get("code_challenge")
+}
+
+func (i *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != i.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            i.URL,
+		"sub":            "oidc-user",
+		"aud":            testIssuerClientID,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          i.nonce,
+		"email":          "user@example.com",
+		"email_verified": true,
+	})
+	idToken.Header["kid"] = testIssuerKeyID
+
+	signed, err := idToken.SignedString(i.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestCallbackOIDC(t *testing.T) {
+	tests := []struct {
+		name string
+		// nonce returns the nonce the provider embeds in the ID token.
+		nonce func(flow *service.AuthFlow) string
+		// foreignChallenge authorizes the code for another verifier, like a code intercepted
+		// from a login the attacker started.
+		foreignChallenge bool
+		wantStatus       int
+		wantMessage      string
+	}{
+		{
+			name:       "valid nonce and verifier",
+			nonce:      func(flow *service.AuthFlow) string { return flow.Nonce },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "mismatched nonce",
+			nonce:       func(*service.AuthFlow) string { return "replayed-nonce" },
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "Invalid ID token nonce",
+		},
+		{
+			name:        "missing nonce",
+			nonce:       func(*service.AuthFlow) string { return "" },
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "Invalid ID token nonce",
+		},
+		{
+			name:             "mismatched code verifier",
+			nonce:            func(flow *service.AuthFlow) string { return flow.Nonce },
+			foreignChallenge: true,
+			wantStatus:       http.StatusInternalServerError,
+			wantMessage:      "Failed to exchange token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+
+			cfg := newTestConfig()
+			provider := service.NewOIDCProvider(configs.OIDCProviderConf{
+				Name:      "keycloak",
+				IssuerURL: issuer.URL,
+				ClientID:  testIssuerClientID,
+				Scopes:    []string{"openid", "email"},
+			}, "http://localhost:8080/api/v1/auth/callback/keycloak")
+			providers := service.NewIdentityProviders(cfg)
+			providers.Register(provider)
+
+			rdb := newTestRedis(t)
+			flow := startFlow(t, rdb, cfg, "keycloak")
+
+			authorized := *flow
+			if tt.foreignChallenge {
+				authorized.Verifier = oauth2.GenerateVerifier()
+			}
+			authURL, err := provider.AuthCodeURL(context.Background(), &authorized)
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			issuer.authorize(t, authURL)
+			issuer.nonce = tt.nonce(flow)
+
+			store := newFakeStore()
+			w := callback(t, store, rdb, cfg, providers, "keycloak", flow.State)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantMessage != "" {
+				if res := decodeError(t, w); res.Message != tt.wantMessage {
+					t.Fatalf("message = %q, want %q", res.Message, tt.wantMessage)
+				}
+			}
+			if created := store.sessions > 0; created != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("session created = %v, want %v", created, tt.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
@@ -1,27 +1,99 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // NewRefreshHandler handles the token refresh process.
 // @Summary      Refresh JWT Token
-// @Description  Generates a new Access Token using a valid Refresh Token, keeping the session active without re-login.
+// @Description  Exchanges a valid Refresh Token for a new Access/Refresh Token pair. Refresh Tokens are single use: presenting an already used token revokes the whole session.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
-// @Param        request    body      map[string]string  true  "Object containing the refresh_token"
-// @Success      200        {object}  map[string]string "New Access Token"
-// @Failure      401        {object}  map[string]any "Invalid or expired Refresh Token"
+// @Param        request    body      dto.RefreshRequestDto  true  "Object containing the refresh_token"
+// @Success      200        {object}  dto.CallbackResponseDto "New Access and Refresh Tokens"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid request body"
+// @Failure      401        {object}  dto.ErrorResponseDto "Invalid, expired or reused Refresh Token"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to refresh session"
 // @Router       /api/v1/auth/refresh [post]
-func NewRefreshHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+func NewRefreshHandler(
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"message": "Refresh endpoint not yet implemented",
+		var req dto.RefreshRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Missing refresh_token",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		hash := service.HashRefreshToken(req.RefreshToken)
+
+		var tokens *dto.CallbackResponseDto
+		err := store.ExecTx(ctx, func(q repository.Querier) error {
+			current, err := q.UseRefreshToken(ctx, hash)
+			if err != nil {
+				return err
+			}
+
+			if err := q.TouchSession(ctx, repository.TouchSessionParams{
+				ID:        current.FamilyID,
+				UserAgent: clientUserAgent(c),
+				IpAddress: clientIP(c),
+			}); err != nil {
+				return err
+			}
+
+			tokens, err = issueTokenPair(ctx, q, cfg, current.UserID, current.FamilyID)
+			return err
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			stored, lookupErr := store.GetRefreshTokenByHash(ctx, hash)
+			if lookupErr == nil && stored.UsedAt.Valid {
+				log.Warn("Refresh token reuse detected, revoking session",
+					zap.String("user_id", stored.UserID.String()),
+					zap.String("session_id", stored.FamilyID.String()),
+				)
+
+				if _, err := service.RevokeSession(ctx, store, rdb, cfg, stored.UserID, stored.FamilyID); err != nil {
+					log.Error("Failed to revoke session", zap.Error(err))
+				}
+			}
+
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponseDto{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid or expired refresh token",
+				Status:  http.StatusText(http.StatusUnauthorized),
+			})
+			return
+		}
+		if err != nil {
+			log.Error("Failed to rotate refresh token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to refresh session",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, tokens)
 	}
 }
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type refreshTestEnv struct {
+	store     *fakeStore
+	rdb       *redis.Client
+	cfg       *configs.Conf
+	sessionID uuid.UUID
+}
+
+// newRefreshTestEnv starts a session and returns the token pair it was issued.
+func newRefreshTestEnv(t *testing.T) (*refreshTestEnv, *dto.CallbackResponseDto) {
+	t.Helper()
+
+	env := &refreshTestEnv{
+		store:     newFakeStore(),
+		rdb:       newTestRedis(t),
+		cfg:       newTestConfig(),
+		sessionID: uuid.New(),
+	}
+
+	tokens, err := issueTokenPair(context.Background(), env.store, env.cfg, uuid.New(), env.sessionID)
+	if err != nil {
+		t.Fatalf("issueTokenPair: %v", err)
+	}
+
+	return env, tokens
+}
+
+// refresh presents the refresh token and returns the response and, on success, the new pair.
+func (env *refreshTestEnv) refresh(t *testing.T, refreshToken string) (*httptest.ResponseRecorder, *dto.CallbackResponseDto) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/auth/refresh", NewRefreshHandler(env.store, env.rdb, env.cfg, zap.NewNop()))
+
+	body, _ := json.Marshal(dto.RefreshRequestDto{RefreshToken: refreshToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+
+	var tokens dto.CallbackResponseDto
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+		t.Fatalf("decode tokens: %v", err)
+	}
+
+	return w, &tokens
+}
+
+// sessionRevoked reports whether access tokens of the session are rejected.
+func (env *refreshTestEnv) sessionRevoked(t *testing.T) bool {
+	t.Helper()
+
+	revoked, err := service.IsTokenRevoked(context.Background(), env.rdb, &service.TokenClaims{
+		ID:        uuid.NewString(),
+		SessionID: env.sessionID,
+	})
+	if err != nil {
+		t.Fatalf("IsTokenRevoked: %v", err)
+	}
+
+	return revoked && env.store.revokedSessions[env.sessionID]
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	env, first := newRefreshTestEnv(t)
+
+	w, second := env.refresh(t, first.RefreshToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("first refresh status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == "" {
+		t.Fatalf("refresh did not issue a new pair")
+	}
+
+	w, third := env.refresh(t, second.RefreshToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("rotated token status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if env.sessionRevoked(t) {
+		t.Fatalf("session revoked after normal rotation")
+	}
+
+	if w, _ := env.refresh(t, second.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Fatalf("second use of rotated token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if !env.sessionRevoked(t) {
+		t.Fatalf("reusing a refresh token did not revoke the session")
+	}
+
+	if w, _ := env.refresh(t, third.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Fatalf("latest token of revoked session status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       func(t *testing.T, env *refreshTestEnv, issued *dto.CallbackResponseDto) string
+		wantStatus  int
+		wantRevoked bool
+	}{
+		{
+			name:       "missing token",
+			token:      func(*testing.T, *refreshTestEnv, *dto.CallbackResponseDto) string { return "" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown token",
+			token:      func(*testing.T, *refreshTestEnv, *dto.CallbackResponseDto) string { return "unknown" },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "reused token",
+			token: func(t *testing.T, env *refreshTestEnv, issued *dto.CallbackResponseDto) string {
+				if w, _ := env.refresh(t, issued.RefreshToken); w.Code != http.StatusOK {
+					t.Fatalf("first refresh status = %d, want %d", w.Code, http.StatusOK)
+				}
+				return issued.RefreshToken
+			},
+			wantStatus:  http.StatusUnauthorized,
+			wantRevoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, issued := newRefreshTestEnv(t)
+
+			w, _ := env.refresh(t, tt.token(t, env, issued))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if revoked := env.sessionRevoked(t); revoked != tt.wantRevoked {
+				t.Fatalf("session revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// startSession creates a session and issues its first token pair in one transaction, so a failed
+// issuance does not leave an active session without tokens behind.
+func startSession(
+	ctx context.Context,
+	store repository.Store,
+	cfg *configs.Conf,
+	arg repository.CreateSessionParams,
+) (*dto.CallbackResponseDto, error) {
+	var tokens *dto.CallbackResponseDto
+	err := store.ExecTx(ctx, func(q repository.Querier) error {
+		session, err := q.CreateSession(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		tokens, err = issueTokenPair(ctx, q, cfg, arg.UserID, session.ID)
+		return err
+	})
+
+	return tokens, err
+}
+
+// issueTokenPair mints a new access token and stores a new refresh token for the given session.
+// The session ID doubles as the refresh token family ID.
+func issueTokenPair(
+	ctx context.Context,
+	repo repository.Querier,
+	cfg *configs.Conf,
+	userID uuid.UUID,
+	sessionID uuid.UUID,
+) (*dto.CallbackResponseDto, error) {
+	accessToken, err := service.GenerateToken(userID, sessionID, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := service.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiry := time.Now().Add(time.Hour * time.Duration(cfg.RefreshTokenExpirationHours))
+
+	if _, err := repo.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
+		UserID:    userID,
+		FamilyID:  sessionID,
+		TokenHash: refreshHash,
+		ExpiresAt: refreshExpiry,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.CallbackResponseDto{
+		Token:                accessToken,
+		TokenType:            "Bearer",
+		ExpiryAt:             time.Now().Add(time.Hour * time.Duration(cfg.JWTExpirationHours)).Unix(),
+		RefreshToken:         refreshToken,
+		RefreshTokenExpiryAt: refreshExpiry.Unix(),
+	}, nil
+}
+
+func clientUserAgent(c *gin.Context) pgtype.Text {
+	userAgent := c.Request.UserAgent()
+	return pgtype.Text{String: userAgent, Valid: userAgent != ""}
+}
+
+func clientIP(c *gin.Context) pgtype.Text {
+	ip := c.ClientIP()
+	return pgtype.Text{String: ip, Valid: ip != ""}
+}
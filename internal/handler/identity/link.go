@@ -0,0 +1,83 @@
+package identity
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewLinkIdentityHandler starts linking a provider identity to the authenticated user.
+// @Summary      Link Identity
+// @Description  Returns the provider authorization URL to open in a browser. Once the user logs in there and confirms on the page the callback shows, that identity is linked to the current user, so either provider signs in to the same account.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        provider   path      string  true  "google, github or a configured OIDC provider name"
+// @Success      200        {object}  dto.LoginResponseDto "Provider authorization URL"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid provider"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Internal server error"
+// @Failure      502        {object}  dto.ErrorResponseDto "Identity provider is unavailable"
+// @Router       /api/v1/auth/identities/{provider} [post]
+func NewLinkIdentityHandler(
+	cfg *configs.Conf,
+	providers *service.IdentityProviders,
+	rdb *redis.Client,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		provider := c.Param("provider")
+
+		identityProvider, ok := providers.Get(provider)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Login provider not supported.",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		flow, err := service.NewAuthFlow(provider, "", cfg.OAuthStateTTL)
+		if err != nil {
+			log.Error("Failed to generate authorization request secrets", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Internal error when starting authentication",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+		flow.LinkUser = userID
+
+		url, err := identityProvider.AuthCodeURL(c.Request.Context(), flow)
+		if err != nil {
+			log.Error("Failed to build provider authorization URL", zap.String("provider", provider), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusBadGateway, dto.ErrorResponseDto{
+				Code:    http.StatusBadGateway,
+				Message: "Identity provider is unavailable",
+				Status:  http.StatusText(http.StatusBadGateway),
+			})
+			return
+		}
+
+		if err := service.SaveAuthFlow(c.Request.Context(), rdb, flow); err != nil {
+			log.Error("Failed to store authorization request", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Internal error when starting authentication",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.LoginResponseDto{URL: url})
+	}
+}
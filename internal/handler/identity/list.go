@@ -0,0 +1,51 @@
+// Package identity contains handlers for managing the provider identities linked to the authenticated user.
+package identity
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewListIdentitiesHandler handles listing the provider identities of the authenticated user.
+// @Summary      List Linked Identities
+// @Description  Lists the providers the current user can sign in with.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200        {array}   dto.IdentityResponseDto "List of linked identities"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to list identities"
+// @Router       /api/v1/auth/identities [get]
+func NewListIdentitiesHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		identities, err := repo.ListUserIdentities(c.Request.Context(), userID)
+		if err != nil {
+			log.Error("Failed to list identities", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to list identities",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		response := make([]dto.IdentityResponseDto, 0, len(identities))
+		for _, identity := range identities {
+			response = append(response, dto.IdentityResponseDto{
+				Provider:      identity.Provider,
+				Email:         identity.Email.String,
+				EmailVerified: identity.EmailVerified,
+				CreatedAt:     identity.CreatedAt.Time,
+			})
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
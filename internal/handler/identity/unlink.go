@@ -0,0 +1,61 @@
+package identity
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewUnlinkIdentityHandler handles unlinking a provider identity from the authenticated user.
+// @Summary      Unlink Identity
+// @Description  Removes the current user's identity with the provider. The last identity cannot be removed. Existing sessions stay valid.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Param        provider   path      string  true  "Provider name"
+// @Success      204        "No Content - Identity unlinked"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      404        {object}  dto.ErrorResponseDto "Identity not found"
+// @Failure      409        {object}  dto.ErrorResponseDto "Cannot unlink the last identity"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to unlink identity"
+// @Router       /api/v1/auth/identities/{provider} [delete]
+func NewUnlinkIdentityHandler(store repository.Store, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		provider := c.Param("provider")
+
+		err := service.UnlinkIdentity(c.Request.Context(), store, userID, provider)
+		switch {
+		case errors.Is(err, service.ErrIdentityNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Identity not found",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		case errors.Is(err, service.ErrLastIdentity):
+			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponseDto{
+				Code:    http.StatusConflict,
+				Message: "Cannot unlink the last identity",
+				Status:  http.StatusText(http.StatusConflict),
+			})
+			return
+		case err != nil:
+			log.Error("Failed to unlink identity", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to unlink identity",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Identity unlinked", zap.String("user_id", userID.String()), zap.String("provider", provider))
+		c.Status(http.StatusNoContent)
+	}
+}
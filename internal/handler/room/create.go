@@ -3,28 +3,126 @@ package room
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
 // NewCreateRoomHandler handles the creation of a new secure room.
 // @Summary      Create Secure Room
-// @Description  Creates a new encrypted private room for ephemeral data sharing.
+// @Description  Creates a new encrypted private room for ephemeral data sharing. The creator becomes the room admin.
 // @Tags         Rooms
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        request    body      map[string]any  true  "Room data (name, optional password, expiration time)"
-// @Success      201        {object}  map[string]any "Created room details"
-// @Failure      400        {object}  map[string]any "Invalid input data"
-// @Failure      401        {object}  map[string]any "Unauthorized"
+// @Param        request    body      dto.CreateRoomRequestDto  true  "Room data (name, optional access code, expiration time)"
+// @Success      201        {object}  dto.RoomResponseDto "Created room details"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid input data"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to create room"
 // @Router       /api/v1/rooms [post]
-func NewCreateRoomHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+func NewCreateRoomHandler(
+	store repository.Store,
+	encryptor *service.Encryptor,
+	log *zap.Logger,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"message": "Create room endpoint not yet implemented",
+		userID, _ := middleware.GetUserID(c)
+
+		var req dto.CreateRoomRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Name must be between 3 and 255 characters and access code between 4 and 128",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Expiration time must be in the future",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		roomID := uuid.New()
+
+		key, err := encryptor.NewRoomKey(c.Request.Context(), roomID)
+		if err != nil {
+			log.Error("Failed to generate room data key", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create room",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		params := repository.CreateRoomParams{
+			ID:         roomID,
+			OwnerID:    userID,
+			Name:       req.Name,
+			WrappedKey: key.Wrapped,
+			KeyVersion: key.Version,
+		}
+
+		if req.AccessCode != "" {
+			hash, err := service.HashAccessCode(req.AccessCode)
+			if err != nil {
+				log.Error("Failed to hash room access code", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+					Code:    http.StatusInternalServerError,
+					Message: "Failed to create room",
+					Status:  http.StatusText(http.StatusInternalServerError),
+				})
+				return
+			}
+			params.AccessCode = pgtype.Text{String: hash, Valid: true}
+		}
+
+		if req.ExpiresAt != nil {
+			params.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+		}
+
+		var room repository.VaultRoom
+		err = store.ExecTx(c.Request.Context(), func(q repository.Querier) error {
+			var err error
+			room, err = q.CreateRoom(c.Request.Context(), params)
+			if err != nil {
+				return err
+			}
+
+			_, err = q.AddMemberToRoom(c.Request.Context(), repository.AddMemberToRoomParams{
+				RoomID: room.ID,
+				UserID: userID,
+				Role:   repository.MemberRoleTypeAdmin,
+			})
+			return err
 		})
+		if err != nil {
+			log.Error("Failed to create room", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create room",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Room created",
+			zap.String("room_id", room.ID.String()),
+			zap.String("owner_id", userID.String()),
+		)
+		c.JSON(http.StatusCreated, toRoomResponse(room))
 	}
 }
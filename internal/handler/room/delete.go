@@ -1,13 +1,20 @@
 package room
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+var errNotRoomOwner = errors.New("user is not the room owner")
+
 // NewDeleteRoomHandler handles the deletion of a secure room.
 // @Summary      Delete Room
 // @Description  Permanently removes a room and all associated secrets (Immediate purge).
@@ -15,12 +22,71 @@ import (
 // @Security     BearerAuth
 // @Param        id         path      string  true  "Room ID (UUID)"
 // @Success      204        "No Content - Room successfully deleted"
-// @Failure      403        {object}  map[string]any "No permission to delete this room"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid room ID"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "No permission to delete this room"
+// @Failure      404        {object}  dto.ErrorResponseDto "Room not found"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to delete room"
 // @Router       /api/v1/rooms/{id} [delete]
-func NewDeleteRoomHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+func NewDeleteRoomHandler(store repository.Store, log *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"message": "Delete room endpoint not yet implemented",
+		userID, _ := middleware.GetUserID(c)
+
+		roomID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid room ID",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		err = store.ExecTx(c.Request.Context(), func(q repository.Querier) error {
+			deleted, err := q.DeleteRoom(c.Request.Context(), repository.DeleteRoomParams{
+				ID:      roomID,
+				OwnerID: userID,
+			})
+			if err != nil || deleted > 0 {
+				return err
+			}
+
+			if _, err := q.GetMemberRole(c.Request.Context(), repository.GetMemberRoleParams{
+				RoomID: roomID,
+				UserID: userID,
+			}); err != nil {
+				return err
+			}
+
+			return errNotRoomOwner
 		})
+
+		switch {
+		case err == nil:
+			log.Info("Room deleted",
+				zap.String("room_id", roomID.String()),
+				zap.String("owner_id", userID.String()),
+			)
+			c.Status(http.StatusNoContent)
+		case errors.Is(err, errNotRoomOwner):
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+				Code:    http.StatusForbidden,
+				Message: "Only the room owner can delete this room",
+				Status:  http.StatusText(http.StatusForbidden),
+			})
+		case errors.Is(err, pgx.ErrNoRows):
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Room not found",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+		default:
+			log.Error("Failed to delete room", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete room",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+		}
 	}
 }
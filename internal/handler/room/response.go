@@ -0,0 +1,23 @@
+package room
+
+import (
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+)
+
+func toRoomResponse(room repository.VaultRoom) dto.RoomResponseDto {
+	response := dto.RoomResponseDto{
+		ID:            room.ID.String(),
+		OwnerID:       room.OwnerID.String(),
+		Name:          room.Name,
+		HasAccessCode: room.AccessCode.Valid,
+		IsActive:      room.IsActive.Bool,
+		CreatedAt:     room.CreatedAt.Time,
+	}
+
+	if room.ExpiresAt.Valid {
+		response.ExpiresAt = &room.ExpiresAt.Time
+	}
+
+	return response
+}
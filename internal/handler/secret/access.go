@@ -0,0 +1,143 @@
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// parseUUIDParam reads a UUID path parameter, aborting with 400 when it is malformed.
+func parseUUIDParam(c *gin.Context, name string, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Status:  http.StatusText(http.StatusBadRequest),
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// requireMembership returns the caller's role in the room, aborting with 403 when they are not a member.
+func requireMembership(
+	c *gin.Context,
+	repo repository.Querier,
+	log *zap.Logger,
+	roomID uuid.UUID,
+	userID uuid.UUID,
+) (repository.MemberRoleType, bool) {
+	role, err := repo.GetMemberRole(c.Request.Context(), repository.GetMemberRoleParams{
+		RoomID: roomID,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+			Code:    http.StatusForbidden,
+			Message: "Access denied to the room",
+			Status:  http.StatusText(http.StatusForbidden),
+		})
+		return "", false
+	}
+	if err != nil {
+		log.Error("Failed to check room membership", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to check room membership",
+			Status:  http.StatusText(http.StatusInternalServerError),
+		})
+		return "", false
+	}
+
+	return role, true
+}
+
+// getRoomKey returns the wrapped data key of the room together with its master key version.
+func getRoomKey(ctx context.Context, q repository.Querier, roomID uuid.UUID) (service.RoomKey, error) {
+	row, err := q.GetRoomKey(ctx, roomID)
+	if err != nil {
+		return service.RoomKey{}, err
+	}
+
+	return service.RoomKey{Wrapped: row.WrappedKey, Version: row.KeyVersion}, nil
+}
+
+// loadRoomKey returns the wrapped data key of the room, generating one for rooms created
+// before envelope encryption was introduced.
+func loadRoomKey(
+	ctx context.Context,
+	q repository.Querier,
+	encryptor *service.Encryptor,
+	roomID uuid.UUID,
+) (service.RoomKey, error) {
+	key, err := getRoomKey(ctx, q, roomID)
+	if err != nil || key.Wrapped != nil {
+		return key, err
+	}
+
+	key, err = encryptor.NewRoomKey(ctx, roomID)
+	if err != nil {
+		return service.RoomKey{}, err
+	}
+
+	updated, err := q.SetRoomKey(ctx, repository.SetRoomKeyParams{
+		ID:         roomID,
+		WrappedKey: key.Wrapped,
+		KeyVersion: key.Version,
+	})
+	if err != nil {
+		return service.RoomKey{}, err
+	}
+	if updated == 0 {
+		return getRoomKey(ctx, q, roomID)
+	}
+
+	return key, nil
+}
+
+// clientCiphertext holds a secret encrypted by the client, which the server stores verbatim.
+type clientCiphertext struct {
+	ciphertext []byte
+	nonce      []byte
+}
+
+// decodeClientCiphertext decodes the ciphertext and nonce of a zero-knowledge secret, aborting
+// with 400 when they are malformed. It returns nil when the request carries plaintext content.
+func decodeClientCiphertext(c *gin.Context, req dto.CreateSecretRequestDto) (*clientCiphertext, bool) {
+	if req.EncryptedContent == "" {
+		return nil, true
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(req.EncryptedContent)
+	if err != nil || len(ciphertext) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+			Code:    http.StatusBadRequest,
+			Message: "encrypted_content must be non-empty base64",
+			Status:  http.StatusText(http.StatusBadRequest),
+		})
+		return nil, false
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(req.Nonce)
+	if err != nil || len(nonce) != service.SecretNonceSize {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+			Code:    http.StatusBadRequest,
+			Message: "nonce must be a base64 encoded 12 byte value",
+			Status:  http.StatusText(http.StatusBadRequest),
+		})
+		return nil, false
+	}
+
+	return &clientCiphertext{ciphertext: ciphertext, nonce: nonce}, true
+}
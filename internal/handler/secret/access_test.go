@@ -0,0 +1,123 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/google/uuid"
+)
+
+// roomKeyQuerier stores a single room key in memory. SetRoomKey behaves like the query and only
+// fills a missing key; raced simulates another request storing its key first.
+type roomKeyQuerier struct {
+	repository.Querier
+	key      repository.GetRoomKeyRow
+	raced    *repository.GetRoomKeyRow
+	setCalls int
+}
+
+func (q *roomKeyQuerier) GetRoomKey(context.Context, uuid.UUID) (repository.GetRoomKeyRow, error) {
+	return q.key, nil
+}
+
+func (q *roomKeyQuerier) SetRoomKey(_ context.Context, arg repository.SetRoomKeyParams) (int64, error) {
+	q.setCalls++
+	if q.raced != nil {
+		q.key = *q.raced
+	}
+	if q.key.WrappedKey != nil {
+		return 0, nil
+	}
+
+	q.key = repository.GetRoomKeyRow{WrappedKey: arg.WrappedKey, KeyVersion: arg.KeyVersion}
+	return 1, nil
+}
+
+func newTestEncryptor(t *testing.T) *service.Encryptor {
+	t.Helper()
+
+	master := make([]byte, 32)
+	if _, err := rand.Read(master); err != nil {
+		t.Fatalf("generate master key: %v", err)
+	}
+	ring, err := service.ParseKeyRing(base64.StdEncoding.EncodeToString(master), 0)
+	if err != nil {
+		t.Fatalf("parse key ring: %v", err)
+	}
+
+	return service.NewEncryptor(ring)
+}
+
+func TestLoadRoomKey(t *testing.T) {
+	ctx := context.Background()
+	encryptor := newTestEncryptor(t)
+	roomID := uuid.New()
+
+	existing, err := encryptor.NewRoomKey(ctx, roomID)
+	if err != nil {
+		t.Fatalf("NewRoomKey: %v", err)
+	}
+	concurrent, err := encryptor.NewRoomKey(ctx, roomID)
+	if err != nil {
+		t.Fatalf("NewRoomKey: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		querier      *roomKeyQuerier
+		wantKey      []byte
+		wantSetCalls int
+	}{
+		{
+			name:    "existing key",
+			querier: &roomKeyQuerier{key: repository.GetRoomKeyRow{WrappedKey: existing.Wrapped, KeyVersion: existing.Version}},
+			wantKey: existing.Wrapped,
+		},
+		{
+			name:         "legacy room",
+			querier:      &roomKeyQuerier{key: repository.GetRoomKeyRow{KeyVersion: 1}},
+			wantSetCalls: 1,
+		},
+		{
+			name: "legacy room raced",
+			querier: &roomKeyQuerier{
+				key:   repository.GetRoomKeyRow{KeyVersion: 1},
+				raced: &repository.GetRoomKeyRow{WrappedKey: concurrent.Wrapped, KeyVersion: concurrent.Version},
+			},
+			wantKey:      concurrent.Wrapped,
+			wantSetCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := loadRoomKey(ctx, tt.querier, encryptor, roomID)
+			if err != nil {
+				t.Fatalf("loadRoomKey: %v", err)
+			}
+			if tt.querier.setCalls != tt.wantSetCalls {
+				t.Fatalf("SetRoomKey called %d times, want %d", tt.querier.setCalls, tt.wantSetCalls)
+			}
+			if !bytes.Equal(key.Wrapped, tt.querier.key.WrappedKey) {
+				t.Fatal("returned key differs from the stored key")
+			}
+			if tt.wantKey != nil && !bytes.Equal(key.Wrapped, tt.wantKey) {
+				t.Fatal("returned key differs from the expected key")
+			}
+
+			secretID := uuid.New()
+			ciphertext, nonce, err := encryptor.EncryptSecret(ctx, key, roomID, secretID, []byte("secret"))
+			if err != nil {
+				t.Fatalf("EncryptSecret: %v", err)
+			}
+			if _, err := encryptor.DecryptSecret(ctx, key, roomID, secretID, ciphertext, nonce); err != nil {
+				t.Fatalf("DecryptSecret: %v", err)
+			}
+		})
+	}
+}
@@ -2,30 +2,163 @@
 package secret
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
 // NewCreateSecretHandler handles the creation of a new secret within a room.
 // @Summary      Add Secret
-// @Description  Stores a new encrypted secret (AES-256) within a specific room.
+// @Description  Stores a new encrypted secret within a specific room. Plaintext `content` is encrypted by the server (AES-256-GCM under the room data key). For zero-knowledge secrets the client sends `encrypted_content`, `nonce` and `algorithm` instead, and the server stores the ciphertext without ever holding the key. Secrets burn after `max_views` reads (1 by default) or once `expires_at` has passed.
 // @Tags         Secrets
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id         path      string          true  "Room ID (UUID)"
-// @Param        request    body      map[string]any  true  "Secret content and visibility settings"
-// @Success      201        {object}  map[string]any "Created secret metadata (ID, date)"
-// @Failure      403        {object}  map[string]any "User is not a member of the room"
-// @Failure      404        {object}  map[string]any "Room not found"
+// @Param        id         path      string                      true  "Room ID (UUID)"
+// @Param        request    body      dto.CreateSecretRequestDto  true  "Secret content"
+// @Success      201        {object}  dto.SecretResponseDto "Created secret metadata (ID, date)"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid input data"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "User is not a member of the room or cannot write to it"
+// @Failure      404        {object}  dto.ErrorResponseDto "Room not found, inactive or expired"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to store secret"
 // @Router       /api/v1/rooms/{id}/secrets [post]
-func NewCreateSecretHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+func NewCreateSecretHandler(
+	store repository.Store,
+	encryptor *service.Encryptor,
+	log *zap.Logger,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"message": "Create secret endpoint not yet implemented",
+		userID, _ := middleware.GetUserID(c)
+
+		roomID, ok := parseUUIDParam(c, "id", "Invalid room ID")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateSecretRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Provide either content (up to 64 KiB) or encrypted_content with nonce and algorithm, and max_views between 1 and 100",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Expiration time must be in the future",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		clientSecret, ok := decodeClientCiphertext(c, req)
+		if !ok {
+			return
+		}
+
+		role, ok := requireMembership(c, store, log, roomID, userID)
+		if !ok {
+			return
+		}
+		if role == repository.MemberRoleTypeViewer {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+				Code:    http.StatusForbidden,
+				Message: "Viewers cannot add secrets to this room",
+				Status:  http.StatusText(http.StatusForbidden),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		secretID := uuid.New()
+
+		var secret repository.SecretItem
+		err := store.ExecTx(ctx, func(q repository.Querier) error {
+			params := repository.CreateSecretParams{
+				ID:        secretID,
+				RoomID:    roomID,
+				CreatorID: userID,
+				MaxViews:  max(req.MaxViews, 1),
+			}
+			if req.ExpiresAt != nil {
+				params.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+			}
+
+			if clientSecret != nil {
+				params.EncryptedContent = clientSecret.ciphertext
+				params.Nonce = clientSecret.nonce
+				params.EncryptionMode = repository.EncryptionModeTypeClient
+				params.Algorithm = req.Algorithm
+			} else {
+				key, err := loadRoomKey(ctx, q, encryptor, roomID)
+				if err != nil {
+					return err
+				}
+
+				params.EncryptedContent, params.Nonce, err = encryptor.EncryptSecret(ctx, key, roomID, secretID, []byte(req.Content))
+				if err != nil {
+					return err
+				}
+				params.EncryptionMode = repository.EncryptionModeTypeServer
+				params.Algorithm = service.SecretAlgorithm
+			}
+
+			var err error
+			secret, err = q.CreateSecret(ctx, params)
+			return err
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Room not found, inactive or expired",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+		if err != nil {
+			log.Error("Failed to store secret", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to store secret",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Secret stored",
+			zap.String("secret_id", secret.ID.String()),
+			zap.String("room_id", roomID.String()),
+			zap.String("encryption_mode", string(secret.EncryptionMode)),
+		)
+
+		res := dto.SecretResponseDto{
+			ID:             secret.ID.String(),
+			RoomID:         secret.RoomID.String(),
+			CreatorID:      secret.CreatorID.String(),
+			EncryptionMode: string(secret.EncryptionMode),
+			Algorithm:      secret.Algorithm,
+			MaxViews:       secret.MaxViews,
+			RemainingViews: secret.RemainingViews,
+			CreatedAt:      secret.CreatedAt.Time,
+		}
+		if secret.ExpiresAt.Valid {
+			res.ExpiresAt = &secret.ExpiresAt.Time
+		}
+
+		c.JSON(http.StatusCreated, res)
 	}
 }
@@ -1,28 +1,131 @@
 package secret
 
 import (
+	"encoding/base64"
+	"errors"
 	"net/http"
 
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 // NewGetSecretHandler handles retrieving and decrypting a specific secret.
 // @Summary      Read Secret (Decrypt)
-// @Description  Retrieves and decrypts the content of a specific secret. May trigger "Burn on Read" rule (delete after reading).
+// @Description  Retrieves and decrypts the content of a specific secret. Each read consumes one of the secret's remaining views in the same statement, and the secret is burned when none are left, so it is never revealed more than `max_views` times ("Burn on Read"). Expired secrets, and secrets of inactive or expired rooms, are treated as burned. Client encrypted secrets are returned as stored in `encrypted_content`, `nonce` and `algorithm`, since the server never holds their key.
 // @Tags         Secrets
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id         path      string  true  "Room ID (UUID)"
 // @Param        secretId   path      string  true  "Secret ID (UUID)"
-// @Success      200        {object}  map[string]any "Decrypted secret content"
-// @Failure      404        {object}  map[string]any "Secret not found or already expired"
+// @Success      200        {object}  dto.SecretContentResponseDto "Decrypted secret content, or ciphertext for client encrypted secrets"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid room or secret ID"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "Access denied to the room"
+// @Failure      404        {object}  dto.ErrorResponseDto "Secret not found, already burned or expired"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to read secret"
 // @Router       /api/v1/rooms/{id}/secrets/{secretId} [get]
-func NewGetSecretHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+func NewGetSecretHandler(
+	store repository.Store,
+	encryptor *service.Encryptor,
+	log *zap.Logger,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotImplemented, gin.H{
-			"message": "Get secret endpoint not yet implemented",
+		userID, _ := middleware.GetUserID(c)
+
+		roomID, ok := parseUUIDParam(c, "id", "Invalid room ID")
+		if !ok {
+			return
+		}
+
+		secretID, ok := parseUUIDParam(c, "secretId", "Invalid secret ID")
+		if !ok {
+			return
+		}
+
+		if _, ok := requireMembership(c, store, log, roomID, userID); !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		// Consuming a view and decrypting share a transaction so a view is never spent without being delivered.
+		// Read committed is enough for the guarded UPDATE and lets concurrent readers wait on the row lock
+		// instead of failing with serialization errors.
+		var secret repository.SecretItem
+		var plaintext []byte
+		err := store.ExecReadCommitted(ctx, func(q repository.Querier) error {
+			var err error
+			secret, err = q.RevealSecret(ctx, repository.RevealSecretParams{
+				ID:     secretID,
+				RoomID: roomID,
+			})
+			if err != nil {
+				return err
+			}
+			if secret.EncryptionMode == repository.EncryptionModeTypeClient {
+				return nil
+			}
+
+			key, err := getRoomKey(ctx, q, roomID)
+			if err != nil {
+				return err
+			}
+
+			plaintext, err = encryptor.DecryptSecret(ctx, key, roomID, secretID, secret.EncryptedContent, secret.Nonce)
+			return err
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Secret not found, already burned or expired",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+		if err != nil {
+			log.Error("Failed to reveal secret", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to read secret",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Secret revealed",
+			zap.String("secret_id", secret.ID.String()),
+			zap.String("room_id", roomID.String()),
+			zap.String("reader_id", userID.String()),
+			zap.Int32("remaining_views", secret.RemainingViews),
+		)
+
+		res := dto.SecretContentResponseDto{
+			ID:             secret.ID.String(),
+			RoomID:         secret.RoomID.String(),
+			CreatorID:      secret.CreatorID.String(),
+			EncryptionMode: string(secret.EncryptionMode),
+			RemainingViews: secret.RemainingViews,
+			CreatedAt:      secret.CreatedAt.Time,
+		}
+		if secret.ExpiresAt.Valid {
+			res.ExpiresAt = &secret.ExpiresAt.Time
+		}
+		if secret.BurnedAt.Valid {
+			res.BurnedAt = &secret.BurnedAt.Time
+		}
+		if secret.EncryptionMode == repository.EncryptionModeTypeClient {
+			res.EncryptedContent = base64.StdEncoding.EncodeToString(secret.EncryptedContent)
+			res.Nonce = base64.StdEncoding.EncodeToString(secret.Nonce)
+			res.Algorithm = secret.Algorithm
+		} else {
+			res.Content = string(plaintext)
+		}
+
+		c.JSON(http.StatusOK, res)
 	}
 }
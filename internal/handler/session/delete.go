@@ -0,0 +1,73 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewDeleteSessionHandler handles revoking a single session of the authenticated user.
+// @Summary      Revoke Session
+// @Description  Revokes one session of the current user, invalidating its Access and Refresh Tokens immediately.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Param        sessionId  path      string  true  "Session ID (UUID)"
+// @Success      204        "No Content - Session revoked"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid session ID"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      404        {object}  dto.ErrorResponseDto "Session not found"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to revoke session"
+// @Router       /api/v1/auth/sessions/{sessionId} [delete]
+func NewDeleteSessionHandler(
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		sessionID, err := uuid.Parse(c.Param("sessionId"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid session ID",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		found, err := service.RevokeSession(c.Request.Context(), repo, rdb, cfg, userID, sessionID)
+		if err != nil {
+			log.Error("Failed to revoke session", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to revoke session",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+		if !found {
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Session not found",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Info("Session revoked",
+			zap.String("user_id", userID.String()),
+			zap.String("session_id", sessionID.String()),
+		)
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NewDeleteAllSessionsHandler handles revoking every session of the authenticated user.
+// @Summary      Log Out Everywhere
+// @Description  Revokes all sessions of the current user, including the one used for this request.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Success      204        "No Content - All sessions revoked"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to revoke sessions"
+// @Router       /api/v1/auth/sessions [delete]
+func NewDeleteAllSessionsHandler(
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	log *zap.Logger,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		count, err := service.RevokeAllSessions(c.Request.Context(), repo, rdb, cfg, userID)
+		if err != nil {
+			log.Error("Failed to revoke sessions", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to revoke sessions",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("All sessions revoked",
+			zap.String("user_id", userID.String()),
+			zap.Int("count", count),
+		)
+		c.Status(http.StatusNoContent)
+	}
+}
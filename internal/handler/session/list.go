@@ -0,0 +1,54 @@
+// Package session contains handlers for managing the authenticated user's login sessions.
+package session
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewListSessionsHandler handles listing the active sessions of the authenticated user.
+// @Summary      List Sessions
+// @Description  Lists the active login sessions of the current user, including device and activity metadata.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200        {array}   dto.SessionResponseDto "List of active sessions"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to list sessions"
+// @Router       /api/v1/auth/sessions [get]
+func NewListSessionsHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _ := middleware.GetClaims(c)
+
+		sessions, err := repo.ListActiveSessionsByUser(c.Request.Context(), claims.UserID)
+		if err != nil {
+			log.Error("Failed to list sessions", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to list sessions",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		response := make([]dto.SessionResponseDto, 0, len(sessions))
+		for _, s := range sessions {
+			response = append(response, dto.SessionResponseDto{
+				ID:         s.ID.String(),
+				Provider:   s.Provider,
+				UserAgent:  s.UserAgent.String,
+				IPAddress:  s.IpAddress.String,
+				CreatedAt:  s.CreatedAt.Time,
+				LastSeenAt: s.LastSeenAt.Time,
+				Current:    s.ID == claims.SessionID,
+			})
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,96 @@
+// Package token contains handlers for managing the authenticated user's personal access tokens.
+package token
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// NewCreateTokenHandler handles creating a personal access token.
+// @Summary      Create Personal Access Token
+// @Description  Creates a token for automation such as CI pipelines, which cannot complete an OAuth redirect. Send it as `Authorization: Bearer vvpat_...`; it is limited to the granted scopes and cannot manage the account, sessions or tokens. The token is only shown in this response.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request    body      dto.CreatePersonalAccessTokenRequestDto  true  "Token name, scopes and optional expiry"
+// @Success      201        {object}  dto.CreatePersonalAccessTokenResponseDto
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid request body"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "Personal access tokens cannot create tokens"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to create token"
+// @Router       /api/v1/auth/tokens [post]
+func NewCreateTokenHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		var req dto.CreatePersonalAccessTokenRequestDto
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid request body",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Expiration time must be in the future",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		token, hash, err := service.GeneratePersonalAccessToken()
+		if err != nil {
+			log.Error("Failed to generate personal access token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create token",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		params := repository.CreatePersonalAccessTokenParams{
+			UserID:    userID,
+			Name:      req.Name,
+			TokenHash: hash,
+			Scopes:    service.NormalizeScopes(req.Scopes),
+		}
+		if req.ExpiresAt != nil {
+			params.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+		}
+
+		pat, err := repo.CreatePersonalAccessToken(c.Request.Context(), params)
+		if err != nil {
+			log.Error("Failed to store personal access token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create token",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		log.Info("Personal access token created",
+			zap.String("user_id", userID.String()),
+			zap.String("token_id", pat.ID.String()),
+			zap.Strings("scopes", pat.Scopes),
+		)
+		c.JSON(http.StatusCreated, dto.CreatePersonalAccessTokenResponseDto{
+			PersonalAccessTokenResponseDto: toTokenResponse(pat),
+			Token:                          token,
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package token
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewListTokensHandler handles listing the personal access tokens of the authenticated user.
+// @Summary      List Personal Access Tokens
+// @Description  Lists the current user's tokens that have not been revoked, including when each was last used, so stale tokens can be spotted and revoked.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200        {array}   dto.PersonalAccessTokenResponseDto "List of tokens"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "Personal access tokens cannot list tokens"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to list tokens"
+// @Router       /api/v1/auth/tokens [get]
+func NewListTokensHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		tokens, err := repo.ListPersonalAccessTokens(c.Request.Context(), userID)
+		if err != nil {
+			log.Error("Failed to list personal access tokens", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to list tokens",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+
+		response := make([]dto.PersonalAccessTokenResponseDto, 0, len(tokens))
+		for _, pat := range tokens {
+			response = append(response, toTokenResponse(pat))
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
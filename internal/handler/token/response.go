@@ -0,0 +1,24 @@
+package token
+
+import (
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+)
+
+func toTokenResponse(pat repository.PersonalAccessToken) dto.PersonalAccessTokenResponseDto {
+	response := dto.PersonalAccessTokenResponseDto{
+		ID:        pat.ID.String(),
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+		CreatedAt: pat.CreatedAt.Time,
+	}
+
+	if pat.ExpiresAt.Valid {
+		response.ExpiresAt = &pat.ExpiresAt.Time
+	}
+	if pat.LastUsedAt.Valid {
+		response.LastUsedAt = &pat.LastUsedAt.Time
+	}
+
+	return response
+}
@@ -0,0 +1,69 @@
+package token
+
+import (
+	"net/http"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NewRevokeTokenHandler handles revoking a personal access token of the authenticated user.
+// @Summary      Revoke Personal Access Token
+// @Description  Revokes one of the current user's tokens. It stops working immediately.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Param        tokenId    path      string  true  "Token ID (UUID)"
+// @Success      204        "No Content - Token revoked"
+// @Failure      400        {object}  dto.ErrorResponseDto "Invalid token ID"
+// @Failure      401        {object}  dto.ErrorResponseDto "Unauthorized"
+// @Failure      403        {object}  dto.ErrorResponseDto "Personal access tokens cannot revoke tokens"
+// @Failure      404        {object}  dto.ErrorResponseDto "Token not found"
+// @Failure      500        {object}  dto.ErrorResponseDto "Failed to revoke token"
+// @Router       /api/v1/auth/tokens/{tokenId} [delete]
+func NewRevokeTokenHandler(repo repository.Querier, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+
+		tokenID, err := uuid.Parse(c.Param("tokenId"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponseDto{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid token ID",
+				Status:  http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+
+		revoked, err := repo.RevokePersonalAccessToken(c.Request.Context(), repository.RevokePersonalAccessTokenParams{
+			ID:     tokenID,
+			UserID: userID,
+		})
+		if err != nil {
+			log.Error("Failed to revoke personal access token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponseDto{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to revoke token",
+				Status:  http.StatusText(http.StatusInternalServerError),
+			})
+			return
+		}
+		if revoked == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponseDto{
+				Code:    http.StatusNotFound,
+				Message: "Token not found",
+				Status:  http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Info("Personal access token revoked",
+			zap.String("user_id", userID.String()),
+			zap.String("token_id", tokenID.String()),
+		)
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,159 @@
+// Package middleware contains Gin middlewares shared across route groups.
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// UserIDKey is the Gin context key under which the authenticated user ID is stored.
+	UserIDKey = "userID"
+	// ClaimsKey is the Gin context key under which the validated token claims are stored.
+	ClaimsKey = "tokenClaims"
+)
+
+// NewAuthMiddleware validates the Bearer JWT or personal access token sent in the Authorization
+// header, rejects revoked tokens and stores the authenticated user ID in the request context.
+func NewAuthMiddleware(
+	cfg *configs.Conf,
+	log *zap.Logger,
+	rdb *redis.Client,
+	repo repository.Querier,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
+		scheme, tokenString, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || tokenString == "" {
+			abortUnauthorized(c, "Missing or malformed Authorization header")
+			return
+		}
+
+		if service.IsPersonalAccessToken(tokenString) {
+			claims, err := service.AuthenticatePersonalAccessToken(c.Request.Context(), repo, tokenString)
+			if errors.Is(err, service.ErrInvalidToken) {
+				log.Warn("Rejected invalid personal access token")
+				abortUnauthorized(c, "Invalid, expired or revoked token")
+				return
+			}
+			if err != nil {
+				log.Error("Failed to verify personal access token", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponseDto{
+					Code:    http.StatusServiceUnavailable,
+					Message: "Unable to verify token",
+					Status:  http.StatusText(http.StatusServiceUnavailable),
+				})
+				return
+			}
+
+			c.Set(UserIDKey, claims.UserID)
+			c.Set(ClaimsKey, claims)
+			c.Next()
+			return
+		}
+
+		claims, err := service.ParseToken(tokenString, cfg)
+		if err != nil {
+			log.Warn("Rejected invalid access token", zap.Error(err))
+			abortUnauthorized(c, "Invalid or expired token")
+			return
+		}
+
+		revoked, err := service.IsTokenRevoked(c.Request.Context(), rdb, claims)
+		if err != nil {
+			log.Error("Failed to check token revocation list", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponseDto{
+				Code:    http.StatusServiceUnavailable,
+				Message: "Unable to verify token",
+				Status:  http.StatusText(http.StatusServiceUnavailable),
+			})
+			return
+		}
+		if revoked {
+			abortUnauthorized(c, "Token has been revoked")
+			return
+		}
+
+		c.Set(UserIDKey, claims.UserID)
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequireScope rejects personal access tokens that were not granted the scope.
+// Session tokens carry every scope.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok || !claims.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+				Code:    http.StatusForbidden,
+				Message: "Token is missing the " + scope + " scope",
+				Status:  http.StatusText(http.StatusForbidden),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession rejects personal access tokens, keeping account, session and token management
+// to users who logged in interactively.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok || claims.IsPersonalAccessToken() {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponseDto{
+				Code:    http.StatusForbidden,
+				Message: "Personal access tokens cannot be used for this operation",
+				Status:  http.StatusText(http.StatusForbidden),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetClaims returns the validated token claims placed in the context by NewAuthMiddleware.
+func GetClaims(c *gin.Context) (*service.TokenClaims, bool) {
+	value, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := value.(*service.TokenClaims)
+	return claims, ok
+}
+
+// GetUserID returns the authenticated user ID placed in the context by NewAuthMiddleware.
+func GetUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, ok := c.Get(UserIDKey)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="vanish-vault-api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponseDto{
+		Code:    http.StatusUnauthorized,
+		Message: message,
+		Status:  http.StatusText(http.StatusUnauthorized),
+	})
+}
@@ -0,0 +1,318 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// patQuerier serves personal access tokens from memory, keyed by their hash.
+type patQuerier struct {
+	repository.Querier
+
+	tokens map[string]repository.PersonalAccessToken
+}
+
+func (q *patQuerier) UsePersonalAccessToken(_ context.Context, tokenHash []byte) (repository.PersonalAccessToken, error) {
+	pat, ok := q.tokens[string(tokenHash)]
+	if !ok {
+		return repository.PersonalAccessToken{}, pgx.ErrNoRows
+	}
+
+	return pat, nil
+}
+
+type authTestEnv struct {
+	cfg    *configs.Conf
+	rdb    *redis.Client
+	router *gin.Engine
+	pat    string
+	userID uuid.UUID
+}
+
+func newAuthTestEnv(t *testing.T) *authTestEnv {
+	t.Helper()
+
+	env := &authTestEnv{
+		cfg:    &configs.Conf{JWTSecret: "test-secret", JWTExpirationHours: 1},
+		rdb:    redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}),
+		userID: uuid.New(),
+	}
+	t.Cleanup(func() { _ = env.rdb.Close() })
+
+	pat, hash, err := service.GeneratePersonalAccessToken()
+	if err != nil {
+		t.Fatalf("GeneratePersonalAccessToken: %v", err)
+	}
+	env.pat = pat
+	repo := &patQuerier{tokens: map[string]repository.PersonalAccessToken{
+		string(hash): {
+			ID:        uuid.New(),
+			UserID:    env.userID,
+			Scopes:    []string{service.ScopeRoomsRead},
+			CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		},
+	}}
+
+	gin.SetMode(gin.TestMode)
+	env.router = gin.New()
+	requireAuth := NewAuthMiddleware(env.cfg, zap.NewNop(), env.rdb, repo)
+	ok := func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		c.String(http.StatusOK, userID.String())
+	}
+	env.router.GET("/protected", requireAuth, ok)
+	env.router.GET("/rooms", requireAuth, RequireScope(service.ScopeRoomsRead), ok)
+	env.router.GET("/secrets", requireAuth, RequireScope(service.ScopeSecretsRead), ok)
+	env.router.GET("/sessions", requireAuth, RequireSession(), ok)
+
+	return env
+}
+
+func (env *authTestEnv) get(path string, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+
+	return w
+}
+
+// validClaims returns the claims GenerateToken would issue for the user.
+func (env *authTestEnv) validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"jti": uuid.NewString(),
+		"sub": env.userID.String(),
+		"sid": uuid.NewString(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+		"iss": "vanish-vault-api",
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, claims jwt.MapClaims, key any) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return token
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	env := newAuthTestEnv(t)
+	secret := []byte(env.cfg.JWTSecret)
+
+	withClaims := func(edit func(t *testing.T, c jwt.MapClaims)) func(t *testing.T) string {
+		return func(t *testing.T) string {
+			claims := env.validClaims()
+			edit(t, claims)
+			return "Bearer " + signToken(t, jwt.SigningMethodHS256, claims, secret)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		authorization func(t *testing.T) string
+		wantStatus    int
+	}{
+		{
+			name:          "valid token",
+			authorization: withClaims(func(*testing.T, jwt.MapClaims) {}),
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "generated token",
+			authorization: generatedToken(env),
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "missing header",
+			authorization: func(*testing.T) string { return "" },
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "wrong scheme",
+			authorization: func(t *testing.T) string {
+				return "Basic " + signToken(t, jwt.SigningMethodHS256, env.validClaims(), secret)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "empty token",
+			authorization: func(*testing.T) string { return "Bearer " },
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "malformed token",
+			authorization: func(*testing.T) string { return "Bearer not-a-jwt" },
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "wrong algorithm",
+			authorization: func(t *testing.T) string {
+				return "Bearer " + signToken(t, jwt.SigningMethodHS512, env.validClaims(), secret)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "alg none",
+			authorization: func(t *testing.T) string {
+				return "Bearer " + signToken(t, jwt.SigningMethodNone, env.validClaims(), jwt.UnsafeAllowNoneSignatureType)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong secret",
+			authorization: func(t *testing.T) string {
+				return "Bearer " + signToken(t, jwt.SigningMethodHS256, env.validClaims(), []byte("other-secret"))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "wrong issuer",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { c["iss"] = "someone-else" }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "expired",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "missing exp",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { delete(c, "exp") }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "missing iat",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { delete(c, "iat") }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "missing jti",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { delete(c, "jti") }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "missing sid",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { delete(c, "sid") }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid sub",
+			authorization: withClaims(func(_ *testing.T, c jwt.MapClaims) { c["sub"] = "not-a-uuid" }),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "revoked jti",
+			authorization: withClaims(func(t *testing.T, c jwt.MapClaims) {
+				if err := service.RevokeToken(context.Background(), env.rdb, c["jti"].(string), time.Now().Add(time.Hour)); err != nil {
+					t.Fatalf("RevokeToken: %v", err)
+				}
+			}),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "revoked sid",
+			authorization: withClaims(func(t *testing.T, c jwt.MapClaims) {
+				sessionID := uuid.MustParse(c["sid"].(string))
+				if err := service.RevokeSessionTokens(context.Background(), env.rdb, sessionID, time.Hour); err != nil {
+					t.Fatalf("RevokeSessionTokens: %v", err)
+				}
+			}),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "revoked user",
+			authorization: withClaims(func(t *testing.T, c jwt.MapClaims) {
+				userID := uuid.New()
+				c["sub"] = userID.String()
+				if err := service.RevokeUserTokens(context.Background(), env.rdb, userID, time.Hour); err != nil {
+					t.Fatalf("RevokeUserTokens: %v", err)
+				}
+			}),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "personal access token",
+			authorization: func(*testing.T) string { return "Bearer " + env.pat },
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "unknown personal access token",
+			authorization: func(*testing.T) string { return "Bearer " + service.PersonalAccessTokenPrefix + "unknown" },
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := env.get("/protected", tt.authorization(t))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != env.userID.String() {
+				t.Fatalf("user id = %q, want %q", w.Body.String(), env.userID)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Fatalf("missing WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func generatedToken(env *authTestEnv) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		token, err := service.GenerateToken(env.userID, uuid.New(), env.cfg)
+		if err != nil {
+			t.Fatalf("GenerateToken: %v", err)
+		}
+		return "Bearer " + token
+	}
+}
+
+func TestAuthMiddlewareScopes(t *testing.T) {
+	env := newAuthTestEnv(t)
+	session := generatedToken(env)(t)
+	pat := "Bearer " + env.pat
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "session token has every scope", path: "/secrets", authorization: session, wantStatus: http.StatusOK},
+		{name: "granted scope", path: "/rooms", authorization: pat, wantStatus: http.StatusOK},
+		{name: "missing scope", path: "/secrets", authorization: pat, wantStatus: http.StatusForbidden},
+		{name: "session required", path: "/sessions", authorization: session, wantStatus: http.StatusOK},
+		{name: "personal access token on session route", path: "/sessions", authorization: pat, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := env.get(tt.path, tt.authorization); w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
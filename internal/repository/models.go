@@ -7,51 +7,52 @@ package repository
 import (
 	"database/sql/driver"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type AuthProviderType string
+type EncryptionModeType string
 
 const (
-	AuthProviderTypeGoogle AuthProviderType = "google"
-	AuthProviderTypeGithub AuthProviderType = "github"
+	EncryptionModeTypeServer EncryptionModeType = "server"
+	EncryptionModeTypeClient EncryptionModeType = "client"
 )
 
-func (e *AuthProviderType) Scan(src interface{}) error {
+func (e *EncryptionModeType) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = AuthProviderType(s)
+		*e = EncryptionModeType(s)
 	case string:
-		*e = AuthProviderType(s)
+		*e = EncryptionModeType(s)
 	default:
-		return fmt.Errorf("unsupported scan type for AuthProviderType: %T", src)
+		return fmt.Errorf("unsupported scan type for EncryptionModeType: %T", src)
 	}
 	return nil
 }
 
-type NullAuthProviderType struct {
-	AuthProviderType AuthProviderType `json:"auth_provider_type"`
-	Valid            bool             `json:"valid"` // Valid is true if AuthProviderType is not NULL
+type NullEncryptionModeType struct {
+	EncryptionModeType EncryptionModeType `json:"encryption_mode_type"`
+	Valid              bool               `json:"valid"` // Valid is true if EncryptionModeType is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullAuthProviderType) Scan(value interface{}) error {
+func (ns *NullEncryptionModeType) Scan(value interface{}) error {
 	if value == nil {
-		ns.AuthProviderType, ns.Valid = "", false
+		ns.EncryptionModeType, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.AuthProviderType.Scan(value)
+	return ns.EncryptionModeType.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullAuthProviderType) Value() (driver.Value, error) {
+func (ns NullEncryptionModeType) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.AuthProviderType), nil
+	return string(ns.EncryptionModeType), nil
 }
 
 type MemberRoleType string
@@ -97,6 +98,35 @@ func (ns NullMemberRoleType) Value() (driver.Value, error) {
 	return string(ns.MemberRoleType), nil
 }
 
+type JobFence struct {
+	Name      string             `json:"name"`
+	Token     int64              `json:"token"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  []byte             `json:"token_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	FamilyID  uuid.UUID          `json:"family_id"`
+	TokenHash []byte             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type RoomMember struct {
 	RoomID    uuid.UUID          `json:"room_id"`
 	UserID    uuid.UUID          `json:"user_id"`
@@ -113,14 +143,39 @@ type SecretItem struct {
 	IsBurned         pgtype.Bool        `json:"is_burned"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	BurnedAt         pgtype.Timestamptz `json:"burned_at"`
+	EncryptionMode   EncryptionModeType `json:"encryption_mode"`
+	Algorithm        string             `json:"algorithm"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	MaxViews         int32              `json:"max_views"`
+	RemainingViews   int32              `json:"remaining_views"`
 }
 
-type User struct {
+type Session struct {
 	ID         uuid.UUID          `json:"id"`
-	Email      pgtype.Text        `json:"email"`
-	Provider   AuthProviderType   `json:"provider"`
-	ProviderID string             `json:"provider_id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Provider   string             `json:"provider"`
+	UserAgent  pgtype.Text        `json:"user_agent"`
+	IpAddress  pgtype.Text        `json:"ip_address"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type User struct {
+	ID            uuid.UUID          `json:"id"`
+	Email         pgtype.Text        `json:"email"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	EmailVerified bool               `json:"email_verified"`
+}
+
+type UserIdentity struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	Provider      string             `json:"provider"`
+	ProviderID    string             `json:"provider_id"`
+	Email         pgtype.Text        `json:"email"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	EmailVerified bool               `json:"email_verified"`
 }
 
 type VaultRoom struct {
@@ -131,4 +186,6 @@ type VaultRoom struct {
 	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
 	IsActive   pgtype.Bool        `json:"is_active"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	WrappedKey []byte             `json:"wrapped_key"`
+	KeyVersion int32              `json:"key_version"`
 }
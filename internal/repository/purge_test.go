@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPurgePersonalAccessTokensKeepsExplicitExpiry(t *testing.T) {
+	q := repository.New(newTestPool(t))
+	ctx := context.Background()
+	user, _ := createTestRoom(t, q, pgtype.Timestamptz{})
+
+	tests := []struct {
+		name      string
+		expiresAt pgtype.Timestamptz
+		wantKept  bool
+	}{
+		{name: "no expiry", wantKept: false},
+		{
+			name:      "explicit expiry",
+			expiresAt: pgtype.Timestamptz{Time: time.Now().Add(24 * time.Hour), Valid: true},
+			wantKept:  true,
+		},
+	}
+
+	ids := make([]uuid.UUID, len(tests))
+	for i, tt := range tests {
+		token, err := q.CreatePersonalAccessToken(ctx, repository.CreatePersonalAccessTokenParams{
+			UserID:    user.ID,
+			Name:      tt.name,
+			TokenHash: []byte(uuid.NewString()),
+			Scopes:    []string{},
+			ExpiresAt: tt.expiresAt,
+		})
+		if err != nil {
+			t.Fatalf("create token: %v", err)
+		}
+		ids[i] = token.ID
+	}
+
+	// Every token is idle: the idle cutoff is later than both were created.
+	if _, err := q.PurgePersonalAccessTokens(ctx, repository.PurgePersonalAccessTokensParams{
+		Cutoff:     pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		IdleCutoff: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}); err != nil {
+		t.Fatalf("purge tokens: %v", err)
+	}
+
+	remaining, err := q.ListPersonalAccessTokens(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("list tokens: %v", err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := false
+			for _, token := range remaining {
+				kept = kept || token.ID == ids[i]
+			}
+			if kept != tt.wantKept {
+				t.Fatalf("token kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
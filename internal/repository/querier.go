@@ -8,20 +8,53 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddMemberToRoom(ctx context.Context, arg AddMemberToRoomParams) (RoomMember, error)
-	BurnSecret(ctx context.Context, id uuid.UUID) error
+	AdvanceJobFence(ctx context.Context, arg AdvanceJobFenceParams) (int64, error)
+	BurnExpiredSecrets(ctx context.Context) (int64, error)
+	CountUserRooms(ctx context.Context, ownerID uuid.UUID) (CountUserRoomsRow, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateRoom(ctx context.Context, arg CreateRoomParams) (VaultRoom, error)
 	CreateSecret(ctx context.Context, arg CreateSecretParams) (SecretItem, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteRoom(ctx context.Context, arg DeleteRoomParams) error
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DeactivateExpiredRooms(ctx context.Context) (int64, error)
+	DeleteExpiredRooms(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error)
+	DeleteRoom(ctx context.Context, arg DeleteRoomParams) (int64, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error)
 	GetMemberRole(ctx context.Context, arg GetMemberRoleParams) (MemberRoleType, error)
-	GetSecretForView(ctx context.Context, arg GetSecretForViewParams) (SecretItem, error)
-	GetUserByProvider(ctx context.Context, arg GetUserByProviderParams) (User, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash []byte) (RefreshToken, error)
+	GetRoomKey(ctx context.Context, id uuid.UUID) (GetRoomKeyRow, error)
+	GetUser(ctx context.Context, id uuid.UUID) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	ListActiveSessionsByUser(ctx context.Context, userID uuid.UUID) ([]Session, error)
 	ListMyRooms(ctx context.Context, userID uuid.UUID) ([]VaultRoom, error)
+	ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error)
+	ListRoomsForRewrap(ctx context.Context, arg ListRoomsForRewrapParams) ([]ListRoomsForRewrapRow, error)
 	ListSecretsByRoom(ctx context.Context, roomID uuid.UUID) ([]ListSecretsByRoomRow, error)
+	ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	PurgeBurnedSecrets(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error)
+	PurgePersonalAccessTokens(ctx context.Context, arg PurgePersonalAccessTokensParams) (int64, error)
+	RevealSecret(ctx context.Context, arg RevealSecretParams) (SecretItem, error)
+	RevokeAllSessionsByUser(ctx context.Context, arg RevokeAllSessionsByUserParams) ([]uuid.UUID, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (uuid.UUID, error)
+	RewrapRoomKey(ctx context.Context, arg RewrapRoomKeyParams) (int64, error)
+	SetRoomKey(ctx context.Context, arg SetRoomKeyParams) (int64, error)
+	SetVerifiedUserEmail(ctx context.Context, arg SetVerifiedUserEmailParams) error
+	TouchSession(ctx context.Context, arg TouchSessionParams) error
+	TransferOwnedRooms(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	UpdateUserIdentityEmail(ctx context.Context, arg UpdateUserIdentityEmailParams) error
+	UsePersonalAccessToken(ctx context.Context, tokenHash []byte) (PersonalAccessToken, error)
+	UseRefreshToken(ctx context.Context, tokenHash []byte) (RefreshToken, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,6 +7,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -36,36 +37,160 @@ func (q *Queries) AddMemberToRoom(ctx context.Context, arg AddMemberToRoomParams
 	return i, err
 }
 
-const burnSecret = `-- name: BurnSecret :exec
+const advanceJobFence = `-- name: AdvanceJobFence :execrows
+INSERT INTO job_fences (name, token)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+SET token = EXCLUDED.token, updated_at = CURRENT_TIMESTAMP
+WHERE job_fences.token <= EXCLUDED.token
+`
+
+type AdvanceJobFenceParams struct {
+	Name  string `json:"name"`
+	Token int64  `json:"token"`
+}
+
+func (q *Queries) AdvanceJobFence(ctx context.Context, arg AdvanceJobFenceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, advanceJobFence, arg.Name, arg.Token)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const burnExpiredSecrets = `-- name: BurnExpiredSecrets :execrows
 UPDATE secret_items
 SET is_burned = true, burned_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE is_burned = false AND expires_at <= CURRENT_TIMESTAMP
 `
 
-func (q *Queries) BurnSecret(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, burnSecret, id)
-	return err
+func (q *Queries) BurnExpiredSecrets(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, burnExpiredSecrets)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const createRoom = `-- name: CreateRoom :one
-INSERT INTO vault_rooms (owner_id, name, access_code, expires_at)
+const countUserRooms = `-- name: CountUserRooms :one
+SELECT
+  (SELECT count(*) FROM vault_rooms r WHERE r.owner_id = $1) AS owned_rooms,
+  (SELECT count(*) FROM room_members m
+   JOIN vault_rooms r ON r.id = m.room_id
+   WHERE m.user_id = $1 AND r.owner_id <> $1) AS joined_rooms
+`
+
+type CountUserRoomsRow struct {
+	OwnedRooms  int64 `json:"owned_rooms"`
+	JoinedRooms int64 `json:"joined_rooms"`
+}
+
+func (q *Queries) CountUserRooms(ctx context.Context, ownerID uuid.UUID) (CountUserRoomsRow, error) {
+	row := q.db.QueryRow(ctx, countUserRooms, ownerID)
+	var i CountUserRoomsRow
+	err := row.Scan(
+		&i.OwnedRooms,
+		&i.JoinedRooms,
+	)
+	return i, err
+}
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+`
+
+type CreatePersonalAccessTokenParams struct {
+	UserID    uuid.UUID          `json:"user_id"`
+	Name      string             `json:"name"`
+	TokenHash []byte             `json:"token_hash"`
+	Scopes    []string           `json:"scopes"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, createPersonalAccessToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
 VALUES ($1, $2, $3, $4)
-RETURNING id, owner_id, name, access_code, expires_at, is_active, created_at
+RETURNING id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+`
+
+type CreateRefreshTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	FamilyID  uuid.UUID `json:"family_id"`
+	TokenHash []byte    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, createRefreshToken,
+		arg.UserID,
+		arg.FamilyID,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRoom = `-- name: CreateRoom :one
+INSERT INTO vault_rooms (id, owner_id, name, access_code, expires_at, wrapped_key, key_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, owner_id, name, access_code, expires_at, is_active, created_at, wrapped_key, key_version
 `
 
 type CreateRoomParams struct {
+	ID         uuid.UUID          `json:"id"`
 	OwnerID    uuid.UUID          `json:"owner_id"`
 	Name       string             `json:"name"`
 	AccessCode pgtype.Text        `json:"access_code"`
 	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	WrappedKey []byte             `json:"wrapped_key"`
+	KeyVersion int32              `json:"key_version"`
 }
 
 func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (VaultRoom, error) {
 	row := q.db.QueryRow(ctx, createRoom,
+		arg.ID,
 		arg.OwnerID,
 		arg.Name,
 		arg.AccessCode,
 		arg.ExpiresAt,
+		arg.WrappedKey,
+		arg.KeyVersion,
 	)
 	var i VaultRoom
 	err := row.Scan(
@@ -76,29 +201,47 @@ func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (VaultRo
 		&i.ExpiresAt,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.WrappedKey,
+		&i.KeyVersion,
 	)
 	return i, err
 }
 
 const createSecret = `-- name: CreateSecret :one
-INSERT INTO secret_items (room_id, creator_id, encrypted_content, nonce)
-VALUES ($1, $2, $3, $4)
-RETURNING id, room_id, creator_id, encrypted_content, nonce, is_burned, created_at, burned_at
+INSERT INTO secret_items (
+  id, room_id, creator_id, encrypted_content, nonce, encryption_mode, algorithm,
+  expires_at, max_views, remaining_views
+)
+SELECT $1, r.id, $3, $4, $5, $6, $7, $8, $9, $9
+FROM vault_rooms r
+WHERE r.id = $2 AND r.is_active = true
+  AND (r.expires_at IS NULL OR r.expires_at > CURRENT_TIMESTAMP)
+RETURNING id, room_id, creator_id, encrypted_content, nonce, is_burned, created_at, burned_at, encryption_mode, algorithm, expires_at, max_views, remaining_views
 `
 
 type CreateSecretParams struct {
-	RoomID           uuid.UUID `json:"room_id"`
-	CreatorID        uuid.UUID `json:"creator_id"`
-	EncryptedContent []byte    `json:"encrypted_content"`
-	Nonce            []byte    `json:"nonce"`
+	ID               uuid.UUID          `json:"id"`
+	RoomID           uuid.UUID          `json:"room_id"`
+	CreatorID        uuid.UUID          `json:"creator_id"`
+	EncryptedContent []byte             `json:"encrypted_content"`
+	Nonce            []byte             `json:"nonce"`
+	EncryptionMode   EncryptionModeType `json:"encryption_mode"`
+	Algorithm        string             `json:"algorithm"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	MaxViews         int32              `json:"max_views"`
 }
 
 func (q *Queries) CreateSecret(ctx context.Context, arg CreateSecretParams) (SecretItem, error) {
 	row := q.db.QueryRow(ctx, createSecret,
+		arg.ID,
 		arg.RoomID,
 		arg.CreatorID,
 		arg.EncryptedContent,
 		arg.Nonce,
+		arg.EncryptionMode,
+		arg.Algorithm,
+		arg.ExpiresAt,
+		arg.MaxViews,
 	)
 	var i SecretItem
 	err := row.Scan(
@@ -110,36 +253,135 @@ func (q *Queries) CreateSecret(ctx context.Context, arg CreateSecretParams) (Sec
 		&i.IsBurned,
 		&i.CreatedAt,
 		&i.BurnedAt,
+		&i.EncryptionMode,
+		&i.Algorithm,
+		&i.ExpiresAt,
+		&i.MaxViews,
+		&i.RemainingViews,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (user_id, provider, user_agent, ip_address)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, provider, user_agent, ip_address, created_at, last_seen_at, revoked_at
+`
+
+type CreateSessionParams struct {
+	UserID    uuid.UUID   `json:"user_id"`
+	Provider  string      `json:"provider"`
+	UserAgent pgtype.Text `json:"user_agent"`
+	IpAddress pgtype.Text `json:"ip_address"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, createSession,
+		arg.UserID,
+		arg.Provider,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (email, provider, provider_id)
-VALUES ($1, $2, $3)
-RETURNING id, email, provider, provider_id, created_at
+INSERT INTO users (email, email_verified)
+VALUES ($1, $2)
+RETURNING id, email, created_at, email_verified
 `
 
 type CreateUserParams struct {
-	Email      pgtype.Text      `json:"email"`
-	Provider   AuthProviderType `json:"provider"`
-	ProviderID string           `json:"provider_id"`
+	Email         pgtype.Text `json:"email"`
+	EmailVerified bool        `json:"email_verified"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.Provider, arg.ProviderID)
+	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.EmailVerified)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.CreatedAt,
+		&i.EmailVerified,
+	)
+	return i, err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, provider_id, email, email_verified)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, provider, provider_id, email, created_at, email_verified
+`
+
+type CreateUserIdentityParams struct {
+	UserID        uuid.UUID   `json:"user_id"`
+	Provider      string      `json:"provider"`
+	ProviderID    string      `json:"provider_id"`
+	Email         pgtype.Text `json:"email"`
+	EmailVerified bool        `json:"email_verified"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.ProviderID,
+		arg.Email,
+		arg.EmailVerified,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
 		&i.Provider,
 		&i.ProviderID,
+		&i.Email,
 		&i.CreatedAt,
+		&i.EmailVerified,
 	)
 	return i, err
 }
 
-const deleteRoom = `-- name: DeleteRoom :exec
+const deactivateExpiredRooms = `-- name: DeactivateExpiredRooms :execrows
+UPDATE vault_rooms
+SET is_active = false
+WHERE is_active = true AND expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeactivateExpiredRooms(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deactivateExpiredRooms)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredRooms = `-- name: DeleteExpiredRooms :execrows
+DELETE FROM vault_rooms
+WHERE expires_at < $1::timestamptz
+`
+
+func (q *Queries) DeleteExpiredRooms(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredRooms, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRoom = `-- name: DeleteRoom :execrows
 DELETE FROM vault_rooms
 WHERE id = $1 AND owner_id = $2
 `
@@ -149,9 +391,43 @@ type DeleteRoomParams struct {
 	OwnerID uuid.UUID `json:"owner_id"`
 }
 
-func (q *Queries) DeleteRoom(ctx context.Context, arg DeleteRoomParams) error {
-	_, err := q.db.Exec(ctx, deleteRoom, arg.ID, arg.OwnerID)
-	return err
+func (q *Queries) DeleteRoom(ctx context.Context, arg DeleteRoomParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRoom, arg.ID, arg.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2
+`
+
+type DeleteUserIdentityParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Provider string    `json:"provider"`
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserIdentity, arg.UserID, arg.Provider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getMemberRole = `-- name: GetMemberRole :one
@@ -171,58 +447,150 @@ func (q *Queries) GetMemberRole(ctx context.Context, arg GetMemberRoleParams) (M
 	return role, err
 }
 
-const getSecretForView = `-- name: GetSecretForView :one
-SELECT id, room_id, creator_id, encrypted_content, nonce, is_burned, created_at, burned_at FROM secret_items
-WHERE id = $1 AND room_id = $2 AND is_burned = false 
-LIMIT 1
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at FROM refresh_tokens
+WHERE token_hash = $1 LIMIT 1
 `
 
-type GetSecretForViewParams struct {
-	ID     uuid.UUID `json:"id"`
-	RoomID uuid.UUID `json:"room_id"`
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash []byte) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByHash, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-func (q *Queries) GetSecretForView(ctx context.Context, arg GetSecretForViewParams) (SecretItem, error) {
-	row := q.db.QueryRow(ctx, getSecretForView, arg.ID, arg.RoomID)
-	var i SecretItem
+const getRoomKey = `-- name: GetRoomKey :one
+SELECT wrapped_key, key_version FROM vault_rooms
+WHERE id = $1 AND is_active = true
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+`
+
+type GetRoomKeyRow struct {
+	WrappedKey []byte `json:"wrapped_key"`
+	KeyVersion int32  `json:"key_version"`
+}
+
+func (q *Queries) GetRoomKey(ctx context.Context, id uuid.UUID) (GetRoomKeyRow, error) {
+	row := q.db.QueryRow(ctx, getRoomKey, id)
+	var i GetRoomKeyRow
+	err := row.Scan(
+		&i.WrappedKey,
+		&i.KeyVersion,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, email, created_at, email_verified FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, id)
+	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.RoomID,
-		&i.CreatorID,
-		&i.EncryptedContent,
-		&i.Nonce,
-		&i.IsBurned,
+		&i.Email,
 		&i.CreatedAt,
-		&i.BurnedAt,
+		&i.EmailVerified,
 	)
 	return i, err
 }
 
-const getUserByProvider = `-- name: GetUserByProvider :one
-SELECT id, email, provider, provider_id, created_at FROM users
-WHERE provider = $1 AND provider_id = $2 LIMIT 1
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT u.id, u.email, u.created_at, u.email_verified FROM users u
+JOIN user_identities i ON i.user_id = u.id
+WHERE i.provider = $1 AND i.provider_id = $2 LIMIT 1
 `
 
-type GetUserByProviderParams struct {
-	Provider   AuthProviderType `json:"provider"`
-	ProviderID string           `json:"provider_id"`
+type GetUserByIdentityParams struct {
+	Provider   string `json:"provider"`
+	ProviderID string `json:"provider_id"`
 }
 
-func (q *Queries) GetUserByProvider(ctx context.Context, arg GetUserByProviderParams) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByProvider, arg.Provider, arg.ProviderID)
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIdentity, arg.Provider, arg.ProviderID)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.CreatedAt,
+		&i.EmailVerified,
+	)
+	return i, err
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT id, user_id, provider, provider_id, email, created_at, email_verified FROM user_identities
+WHERE provider = $1 AND provider_id = $2 LIMIT 1
+`
+
+type GetUserIdentityParams struct {
+	Provider   string `json:"provider"`
+	ProviderID string `json:"provider_id"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getUserIdentity, arg.Provider, arg.ProviderID)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
 		&i.Provider,
 		&i.ProviderID,
+		&i.Email,
 		&i.CreatedAt,
+		&i.EmailVerified,
 	)
 	return i, err
 }
 
+const listActiveSessionsByUser = `-- name: ListActiveSessionsByUser :many
+SELECT id, user_id, provider, user_agent, ip_address, created_at, last_seen_at, revoked_at FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY last_seen_at DESC
+`
+
+func (q *Queries) ListActiveSessionsByUser(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listActiveSessionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMyRooms = `-- name: ListMyRooms :many
-SELECT r.id, r.owner_id, r.name, r.access_code, r.expires_at, r.is_active, r.created_at FROM vault_rooms r
+SELECT r.id, r.owner_id, r.name, r.access_code, r.expires_at, r.is_active, r.created_at, r.wrapped_key, r.key_version FROM vault_rooms r
 JOIN room_members m ON r.id = m.room_id
 WHERE m.user_id = $1 AND r.is_active = true
 `
@@ -244,6 +612,87 @@ func (q *Queries) ListMyRooms(ctx context.Context, userID uuid.UUID) ([]VaultRoo
 			&i.ExpiresAt,
 			&i.IsActive,
 			&i.CreatedAt,
+			&i.WrappedKey,
+			&i.KeyVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPersonalAccessTokens = `-- name: ListPersonalAccessTokens :many
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error) {
+	rows, err := q.db.Query(ctx, listPersonalAccessTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PersonalAccessToken{}
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Scopes,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomsForRewrap = `-- name: ListRoomsForRewrap :many
+SELECT id, wrapped_key, key_version FROM vault_rooms
+WHERE wrapped_key IS NOT NULL AND key_version <> $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListRoomsForRewrapParams struct {
+	KeyVersion int32     `json:"key_version"`
+	AfterID    uuid.UUID `json:"after_id"`
+	BatchSize  int32     `json:"batch_size"`
+}
+
+type ListRoomsForRewrapRow struct {
+	ID         uuid.UUID `json:"id"`
+	WrappedKey []byte    `json:"wrapped_key"`
+	KeyVersion int32     `json:"key_version"`
+}
+
+func (q *Queries) ListRoomsForRewrap(ctx context.Context, arg ListRoomsForRewrapParams) ([]ListRoomsForRewrapRow, error) {
+	rows, err := q.db.Query(ctx, listRoomsForRewrap, arg.KeyVersion, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRoomsForRewrapRow{}
+	for rows.Next() {
+		var i ListRoomsForRewrapRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WrappedKey,
+			&i.KeyVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -259,6 +708,7 @@ const listSecretsByRoom = `-- name: ListSecretsByRoom :many
 SELECT id, creator_id, created_at, is_burned 
 FROM secret_items
 WHERE room_id = $1 AND is_burned = false
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
 `
 
 type ListSecretsByRoomRow struct {
@@ -292,3 +742,373 @@ func (q *Queries) ListSecretsByRoom(ctx context.Context, roomID uuid.UUID) ([]Li
 	}
 	return items, nil
 }
+
+const listUserIdentities = `-- name: ListUserIdentities :many
+SELECT id, user_id, provider, provider_id, email, created_at, email_verified FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, listUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserIdentity{}
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.ProviderID,
+			&i.Email,
+			&i.CreatedAt,
+			&i.EmailVerified,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeBurnedSecrets = `-- name: PurgeBurnedSecrets :execrows
+DELETE FROM secret_items
+WHERE is_burned = true AND burned_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeBurnedSecrets(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeBurnedSecrets, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgePersonalAccessTokens = `-- name: PurgePersonalAccessTokens :execrows
+DELETE FROM personal_access_tokens
+WHERE revoked_at < $1
+   OR expires_at < $1
+   OR (expires_at IS NULL AND COALESCE(last_used_at, created_at) < $2)
+`
+
+type PurgePersonalAccessTokensParams struct {
+	Cutoff     pgtype.Timestamptz `json:"cutoff"`
+	IdleCutoff pgtype.Timestamptz `json:"idle_cutoff"`
+}
+
+func (q *Queries) PurgePersonalAccessTokens(ctx context.Context, arg PurgePersonalAccessTokensParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgePersonalAccessTokens, arg.Cutoff, arg.IdleCutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revealSecret = `-- name: RevealSecret :one
+UPDATE secret_items s
+SET remaining_views = s.remaining_views - 1,
+    is_burned = s.remaining_views <= 1,
+    burned_at = CASE WHEN s.remaining_views <= 1 THEN CURRENT_TIMESTAMP ELSE s.burned_at END
+FROM vault_rooms r
+WHERE s.id = $1 AND s.room_id = $2 AND s.is_burned = false
+  AND (s.expires_at IS NULL OR s.expires_at > CURRENT_TIMESTAMP)
+  AND r.id = s.room_id AND r.is_active = true
+  AND (r.expires_at IS NULL OR r.expires_at > CURRENT_TIMESTAMP)
+RETURNING s.id, s.room_id, s.creator_id, s.encrypted_content, s.nonce, s.is_burned, s.created_at, s.burned_at, s.encryption_mode, s.algorithm, s.expires_at, s.max_views, s.remaining_views
+`
+
+type RevealSecretParams struct {
+	ID     uuid.UUID `json:"id"`
+	RoomID uuid.UUID `json:"room_id"`
+}
+
+func (q *Queries) RevealSecret(ctx context.Context, arg RevealSecretParams) (SecretItem, error) {
+	row := q.db.QueryRow(ctx, revealSecret, arg.ID, arg.RoomID)
+	var i SecretItem
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.CreatorID,
+		&i.EncryptedContent,
+		&i.Nonce,
+		&i.IsBurned,
+		&i.CreatedAt,
+		&i.BurnedAt,
+		&i.EncryptionMode,
+		&i.Algorithm,
+		&i.ExpiresAt,
+		&i.MaxViews,
+		&i.RemainingViews,
+	)
+	return i, err
+}
+
+const revokeAllSessionsByUser = `-- name: RevokeAllSessionsByUser :many
+UPDATE sessions
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1
+  AND (revoked_at IS NULL OR revoked_at > $2)
+RETURNING id
+`
+
+type RevokeAllSessionsByUserParams struct {
+	UserID       uuid.UUID          `json:"user_id"`
+	RevokedAfter pgtype.Timestamptz `json:"revoked_after"`
+}
+
+func (q *Queries) RevokeAllSessionsByUser(ctx context.Context, arg RevokeAllSessionsByUserParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, revokeAllSessionsByUser, arg.UserID, arg.RevokedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokePersonalAccessToken = `-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokePersonalAccessTokenParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	return err
+}
+
+const revokeSession = `-- name: RevokeSession :one
+UPDATE sessions
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING id
+`
+
+type RevokeSessionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, revokeSession, arg.ID, arg.UserID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const rewrapRoomKey = `-- name: RewrapRoomKey :execrows
+UPDATE vault_rooms
+SET wrapped_key = $1, key_version = $2
+WHERE id = $3 AND key_version = $4
+`
+
+type RewrapRoomKeyParams struct {
+	WrappedKey    []byte    `json:"wrapped_key"`
+	NewKeyVersion int32     `json:"new_key_version"`
+	ID            uuid.UUID `json:"id"`
+	OldKeyVersion int32     `json:"old_key_version"`
+}
+
+func (q *Queries) RewrapRoomKey(ctx context.Context, arg RewrapRoomKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rewrapRoomKey,
+		arg.WrappedKey,
+		arg.NewKeyVersion,
+		arg.ID,
+		arg.OldKeyVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setRoomKey = `-- name: SetRoomKey :execrows
+UPDATE vault_rooms
+SET wrapped_key = $2, key_version = $3
+WHERE id = $1 AND wrapped_key IS NULL
+`
+
+type SetRoomKeyParams struct {
+	ID         uuid.UUID `json:"id"`
+	WrappedKey []byte    `json:"wrapped_key"`
+	KeyVersion int32     `json:"key_version"`
+}
+
+func (q *Queries) SetRoomKey(ctx context.Context, arg SetRoomKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setRoomKey, arg.ID, arg.WrappedKey, arg.KeyVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setVerifiedUserEmail = `-- name: SetVerifiedUserEmail :exec
+UPDATE users
+SET email = $2, email_verified = true
+WHERE id = $1 AND (email IS NULL OR NOT email_verified)
+`
+
+type SetVerifiedUserEmailParams struct {
+	ID    uuid.UUID   `json:"id"`
+	Email pgtype.Text `json:"email"`
+}
+
+func (q *Queries) SetVerifiedUserEmail(ctx context.Context, arg SetVerifiedUserEmailParams) error {
+	_, err := q.db.Exec(ctx, setVerifiedUserEmail, arg.ID, arg.Email)
+	return err
+}
+
+const touchSession = `-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP, user_agent = $2, ip_address = $3
+WHERE id = $1
+`
+
+type TouchSessionParams struct {
+	ID        uuid.UUID   `json:"id"`
+	UserAgent pgtype.Text `json:"user_agent"`
+	IpAddress pgtype.Text `json:"ip_address"`
+}
+
+func (q *Queries) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	_, err := q.db.Exec(ctx, touchSession, arg.ID, arg.UserAgent, arg.IpAddress)
+	return err
+}
+
+const transferOwnedRooms = `-- name: TransferOwnedRooms :execrows
+WITH successors AS (
+  SELECT DISTINCT ON (m.room_id) m.room_id, m.user_id
+  FROM room_members m
+  JOIN vault_rooms r ON r.id = m.room_id
+  WHERE r.owner_id = $1 AND m.user_id <> $1
+  ORDER BY m.room_id, m.role, m.created_at
+), transferred AS (
+  UPDATE vault_rooms r
+  SET owner_id = s.user_id
+  FROM successors s
+  WHERE r.id = s.room_id
+  RETURNING r.id, r.owner_id
+)
+UPDATE room_members m
+SET role = 'admin'
+FROM transferred t
+WHERE m.room_id = t.id AND m.user_id = t.owner_id
+`
+
+func (q *Queries) TransferOwnedRooms(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, transferOwnedRooms, ownerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserIdentityEmail = `-- name: UpdateUserIdentityEmail :exec
+UPDATE user_identities
+SET email = $3, email_verified = $4
+WHERE provider = $1 AND provider_id = $2
+`
+
+type UpdateUserIdentityEmailParams struct {
+	Provider      string      `json:"provider"`
+	ProviderID    string      `json:"provider_id"`
+	Email         pgtype.Text `json:"email"`
+	EmailVerified bool        `json:"email_verified"`
+}
+
+func (q *Queries) UpdateUserIdentityEmail(ctx context.Context, arg UpdateUserIdentityEmailParams) error {
+	_, err := q.db.Exec(ctx, updateUserIdentityEmail,
+		arg.Provider,
+		arg.ProviderID,
+		arg.Email,
+		arg.EmailVerified,
+	)
+	return err
+}
+
+const usePersonalAccessToken = `-- name: UsePersonalAccessToken :one
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+RETURNING id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+`
+
+func (q *Queries) UsePersonalAccessToken(ctx context.Context, tokenHash []byte) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, usePersonalAccessToken, tokenHash)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useRefreshToken = `-- name: UseRefreshToken :one
+UPDATE refresh_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+`
+
+func (q *Queries) UseRefreshToken(ctx context.Context, tokenHash []byte) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, useRefreshToken, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
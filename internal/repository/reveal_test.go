@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TestRevealSecretConcurrent reveals a secret from many readers at once through the same
+// transaction the secret handler uses. Every view must be served exactly once and the other
+// readers must see no rows, never a serialization failure.
+func TestRevealSecretConcurrent(t *testing.T) {
+	pool := newTestPool(t)
+	store := repository.NewStore(pool)
+	q := repository.New(pool)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		maxViews int32
+	}{
+		{name: "single view", maxViews: 1},
+		{name: "several views", maxViews: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, room := createTestRoom(t, q, pgtype.Timestamptz{})
+
+			secret, err := createTestSecret(t, q, user, room, tt.maxViews)
+			if err != nil {
+				t.Fatalf("create secret: %v", err)
+			}
+
+			const readers = 32
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				revealed int
+				notFound int
+				failures []error
+			)
+
+			start := make(chan struct{})
+			for range readers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+
+					err := store.ExecReadCommitted(ctx, func(q repository.Querier) error {
+						_, err := q.RevealSecret(ctx, repository.RevealSecretParams{ID: secret.ID, RoomID: room.ID})
+						return err
+					})
+
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						revealed++
+					case errors.Is(err, pgx.ErrNoRows):
+						notFound++
+					default:
+						failures = append(failures, err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if len(failures) > 0 {
+				t.Fatalf("unexpected errors: %v", failures)
+			}
+			if revealed != int(tt.maxViews) {
+				t.Fatalf("revealed %d times, want exactly %d", revealed, tt.maxViews)
+			}
+			if notFound != readers-int(tt.maxViews) {
+				t.Fatalf("%d readers saw no rows, want %d", notFound, readers-int(tt.maxViews))
+			}
+		})
+	}
+}
+
+func TestRevealSecretInactiveRoom(t *testing.T) {
+	pool := newTestPool(t)
+	q := repository.New(pool)
+	ctx := context.Background()
+	user, room := createTestRoom(t, q, pgtype.Timestamptz{})
+
+	secret, err := createTestSecret(t, q, user, room, 1)
+	if err != nil {
+		t.Fatalf("create secret: %v", err)
+	}
+
+	if _, err := pool.Exec(ctx, "UPDATE vault_rooms SET is_active = false WHERE id = $1", room.ID); err != nil {
+		t.Fatalf("deactivate room: %v", err)
+	}
+
+	_, err = q.RevealSecret(ctx, repository.RevealSecretParams{ID: secret.ID, RoomID: room.ID})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("RevealSecret error = %v, want %v", err, pgx.ErrNoRows)
+	}
+}
+
+func TestCreateSecretExpiredRoom(t *testing.T) {
+	q := repository.New(newTestPool(t))
+	user, room := createTestRoom(t, q, pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true})
+
+	_, err := createTestSecret(t, q, user, room, 1)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("CreateSecret error = %v, want %v", err, pgx.ErrNoRows)
+	}
+}
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRevokeAllSessionsByUserRetry(t *testing.T) {
+	q := repository.New(newTestPool(t))
+	ctx := context.Background()
+	user, _ := createTestRoom(t, q, pgtype.Timestamptz{})
+
+	session, err := q.CreateSession(ctx, repository.CreateSessionParams{UserID: user.ID, Provider: "google"})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		revokedAfter time.Time
+		want         int
+	}{
+		{name: "active session", revokedAfter: time.Now().Add(-time.Hour), want: 1},
+		{name: "retry within access token lifetime", revokedAfter: time.Now().Add(-time.Hour), want: 1},
+		{name: "access tokens already expired", revokedAfter: time.Now().Add(time.Minute), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := q.RevokeAllSessionsByUser(ctx, repository.RevokeAllSessionsByUserParams{
+				UserID:       user.ID,
+				RevokedAfter: pgtype.Timestamptz{Time: tt.revokedAfter, Valid: true},
+			})
+			if err != nil {
+				t.Fatalf("RevokeAllSessionsByUser: %v", err)
+			}
+			if len(ids) != tt.want {
+				t.Fatalf("revoked %d sessions, want %d", len(ids), tt.want)
+			}
+			if tt.want == 1 && ids[0] != session.ID {
+				t.Fatalf("revoked session %v, want %v", ids[0], session.ID)
+			}
+		})
+	}
+}
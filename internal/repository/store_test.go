@@ -0,0 +1,44 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestExecFencedRejectsStaleTokens(t *testing.T) {
+	store := repository.NewStore(newTestPool(t))
+	ctx := context.Background()
+	job := "test-" + uuid.NewString()
+
+	tests := []struct {
+		name  string
+		token int64
+		want  error
+	}{
+		{name: "first holder", token: 5},
+		{name: "same holder again", token: 5},
+		{name: "stale holder", token: 4, want: repository.ErrStaleFencingToken},
+		{name: "next holder", token: 6},
+		{name: "previous holder", token: 5, want: repository.ErrStaleFencingToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			err := store.ExecFenced(ctx, job, tt.token, func(repository.Querier) error {
+				ran = true
+				return nil
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ExecFenced error = %v, want %v", err, tt.want)
+			}
+			if ran != (tt.want == nil) {
+				t.Fatalf("fn ran = %v, want %v", ran, tt.want == nil)
+			}
+		})
+	}
+}
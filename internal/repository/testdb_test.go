@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/db/migrations"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/golang-migrate/migrate/v4"
+
+	// Driver necessary for golang-migrate to talk to postgres
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestPool connects to the database named by TEST_DATABASE_URL and applies every migration.
+// Tests that need a real database are skipped when the variable is not set.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		t.Fatalf("create migration source: %v", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", source, dsn)
+	if err != nil {
+		t.Fatalf("create migration instance: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// createTestUser inserts a user with a unique email.
+func createTestUser(t *testing.T, q *repository.Queries) repository.User {
+	t.Helper()
+
+	user, err := q.CreateUser(context.Background(), repository.CreateUserParams{
+		Email: pgtype.Text{String: uuid.NewString() + "@example.com", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	return user
+}
+
+// createTestRoom inserts a user and a room owned by that user, expiring at expiresAt when it is valid.
+func createTestRoom(
+	t *testing.T,
+	q *repository.Queries,
+	expiresAt pgtype.Timestamptz,
+) (repository.User, repository.VaultRoom) {
+	t.Helper()
+
+	user := createTestUser(t, q)
+
+	room, err := q.CreateRoom(context.Background(), repository.CreateRoomParams{
+		ID:         uuid.New(),
+		OwnerID:    user.ID,
+		Name:       "test room",
+		ExpiresAt:  expiresAt,
+		KeyVersion: 1,
+	})
+	if err != nil {
+		t.Fatalf("create room: %v", err)
+	}
+
+	return user, room
+}
+
+// createTestSecret inserts a server encrypted secret that can be revealed maxViews times.
+func createTestSecret(
+	t *testing.T,
+	q *repository.Queries,
+	user repository.User,
+	room repository.VaultRoom,
+	maxViews int32,
+) (repository.SecretItem, error) {
+	t.Helper()
+
+	return q.CreateSecret(context.Background(), repository.CreateSecretParams{
+		ID:               uuid.New(),
+		RoomID:           room.ID,
+		CreatorID:        user.ID,
+		EncryptedContent: []byte("ciphertext"),
+		Nonce:            make([]byte, 12),
+		EncryptionMode:   repository.EncryptionModeTypeServer,
+		Algorithm:        "AES-256-GCM",
+		MaxViews:         maxViews,
+	})
+}
@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestTransferOwnedRooms(t *testing.T) {
+	pool := newTestPool(t)
+	q := repository.New(pool)
+	ctx := context.Background()
+	owner, _ := createTestRoom(t, q, pgtype.Timestamptz{})
+
+	type member struct {
+		role   repository.MemberRoleType
+		joined time.Duration
+	}
+
+	tests := []struct {
+		name    string
+		members []member
+		// successor indexes members, or is -1 when the room has nobody to take it over.
+		successor int
+	}{
+		{
+			name: "higher role wins over earlier join",
+			members: []member{
+				{role: repository.MemberRoleTypeViewer, joined: -3 * time.Hour},
+				{role: repository.MemberRoleTypeEditor, joined: -2 * time.Hour},
+				{role: repository.MemberRoleTypeAdmin, joined: -time.Hour},
+			},
+			successor: 2,
+		},
+		{
+			name: "earliest join breaks a role tie",
+			members: []member{
+				{role: repository.MemberRoleTypeEditor, joined: -time.Hour},
+				{role: repository.MemberRoleTypeEditor, joined: -2 * time.Hour},
+				{role: repository.MemberRoleTypeViewer, joined: -3 * time.Hour},
+			},
+			successor: 1,
+		},
+		{name: "owner is the only member", successor: -1},
+	}
+
+	rooms := make([]repository.VaultRoom, len(tests))
+	members := make([][]repository.User, len(tests))
+	for i, tt := range tests {
+		room, err := q.CreateRoom(ctx, repository.CreateRoomParams{
+			ID:         uuid.New(),
+			OwnerID:    owner.ID,
+			Name:       tt.name,
+			KeyVersion: 1,
+		})
+		if err != nil {
+			t.Fatalf("create room: %v", err)
+		}
+		rooms[i] = room
+
+		if _, err := q.AddMemberToRoom(ctx, repository.AddMemberToRoomParams{
+			RoomID: room.ID,
+			UserID: owner.ID,
+			Role:   repository.MemberRoleTypeAdmin,
+		}); err != nil {
+			t.Fatalf("add owner: %v", err)
+		}
+
+		for _, m := range tt.members {
+			user := createTestUser(t, q)
+			if _, err := q.AddMemberToRoom(ctx, repository.AddMemberToRoomParams{
+				RoomID: room.ID,
+				UserID: user.ID,
+				Role:   m.role,
+			}); err != nil {
+				t.Fatalf("add member: %v", err)
+			}
+			if _, err := pool.Exec(ctx,
+				"UPDATE room_members SET created_at = $3 WHERE room_id = $1 AND user_id = $2",
+				room.ID, user.ID, time.Now().Add(m.joined),
+			); err != nil {
+				t.Fatalf("set join date: %v", err)
+			}
+			members[i] = append(members[i], user)
+		}
+	}
+
+	transferred, err := q.TransferOwnedRooms(ctx, owner.ID)
+	if err != nil {
+		t.Fatalf("TransferOwnedRooms: %v", err)
+	}
+	if transferred != 2 {
+		t.Fatalf("transferred %d rooms, want 2", transferred)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ownerID uuid.UUID
+			if err := pool.QueryRow(ctx, "SELECT owner_id FROM vault_rooms WHERE id = $1", rooms[i].ID).Scan(&ownerID); err != nil {
+				t.Fatalf("load room owner: %v", err)
+			}
+
+			if tt.successor < 0 {
+				if ownerID != owner.ID {
+					t.Fatalf("room owner = %v, want it to stay with %v", ownerID, owner.ID)
+				}
+				return
+			}
+
+			successor := members[i][tt.successor]
+			if ownerID != successor.ID {
+				t.Fatalf("room owner = %v, want %v", ownerID, successor.ID)
+			}
+
+			role, err := q.GetMemberRole(ctx, repository.GetMemberRoleParams{RoomID: rooms[i].ID, UserID: successor.ID})
+			if err != nil {
+				t.Fatalf("GetMemberRole: %v", err)
+			}
+			if role != repository.MemberRoleTypeAdmin {
+				t.Fatalf("successor role = %q, want %q", role, repository.MemberRoleTypeAdmin)
+			}
+
+			for j, other := range members[i] {
+				if j == tt.successor {
+					continue
+				}
+				role, err := q.GetMemberRole(ctx, repository.GetMemberRoleParams{RoomID: rooms[i].ID, UserID: other.ID})
+				if err != nil {
+					t.Fatalf("GetMemberRole: %v", err)
+				}
+				if role != tt.members[j].role {
+					t.Fatalf("member %d role = %q, want it unchanged as %q", j, role, tt.members[j].role)
+				}
+			}
+		})
+	}
+}
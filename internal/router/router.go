@@ -2,7 +2,12 @@
 package router
 
 import (
+	"context"
+	"errors"
+	"net/http"
+
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -11,10 +16,11 @@ import (
 
 // Router struct holds the configuration and handlers for setting up routes.
 type Router struct {
-	cfg *configs.Conf
-	log *zap.Logger
-	db  *pgxpool.Pool
-	rdb *redis.Client
+	cfg       *configs.Conf
+	log       *zap.Logger
+	db        *pgxpool.Pool
+	rdb       *redis.Client
+	encryptor *service.Encryptor
 }
 
 // NewRouter creates a new Router instance with the given configuration and handlers.
@@ -23,17 +29,21 @@ func NewRouter(
 	log *zap.Logger,
 	db *pgxpool.Pool,
 	rdb *redis.Client,
+	encryptor *service.Encryptor,
 ) *Router {
 	return &Router{
-		cfg: cfg,
-		log: log,
-		db:  db,
-		rdb: rdb,
+		cfg:       cfg,
+		log:       log,
+		db:        db,
+		rdb:       rdb,
+		encryptor: encryptor,
 	}
 }
 
-// Setup initializes the Gin engine, sets up routes, and returns the configured engine.
-func (r *Router) Setup() {
+// Setup initializes the Gin engine, sets up routes, and serves HTTP until the context is cancelled.
+// On cancellation the server stops accepting connections and drains in-flight requests for up to
+// SHUTDOWN_TIMEOUT before returning.
+func (r *Router) Setup(ctx context.Context) error {
 	router := gin.New()
 	gin.SetMode(r.cfg.GinMode)
 
@@ -41,9 +51,57 @@ func (r *Router) Setup() {
 
 	r.setupRoutes(router)
 
-	r.log.Info("Starting server on port 8080")
+	tlsConfig, err := configs.NewTLSConfig(r.cfg)
+	if err != nil {
+		return err
+	}
 
-	if err := router.Run(":8080"); err != nil {
-		r.log.Fatal("Failed to start server", zap.Error(err))
+	srv := &http.Server{
+		Addr:              r.cfg.HTTPAddr,
+		Handler:           router,
+		TLSConfig:         tlsConfig,
+		ReadTimeout:       r.cfg.HTTPReadTimeout,
+		ReadHeaderTimeout: r.cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      r.cfg.HTTPWriteTimeout,
+		IdleTimeout:       r.cfg.HTTPIdleTimeout,
 	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		r.log.Info("Starting server",
+			zap.String("addr", srv.Addr),
+			zap.Bool("tls", tlsConfig != nil),
+			zap.Bool("mtls", tlsConfig != nil && tlsConfig.ClientCAs != nil),
+		)
+
+		if tlsConfig != nil {
+			// Certificates are already loaded into TLSConfig.
+			serveErr <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	r.log.Info("Shutting down server, draining in-flight requests",
+		zap.Duration("timeout", r.cfg.ShutdownTimeout),
+	)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
@@ -1,11 +1,17 @@
 package router
 
 import (
+	accountHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/account"
 	authHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/auth"
+	identityHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/identity"
 	infraHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/infra"
 	roomHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/room"
 	secretHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/secret"
+	sessionHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/session"
+	tokenHandler "github.com/TheCodeBreakerK/vanish-vault-api/internal/handler/token"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/middleware"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -14,7 +20,10 @@ import (
 func (r *Router) setupRoutes(engine *gin.Engine) {
 	r.log.Info("Setting up all routes")
 
-	repo := repository.New(r.db)
+	store := repository.NewStore(r.db)
+	requireAuth := middleware.NewAuthMiddleware(r.cfg, r.log, r.rdb, store)
+	requireSession := middleware.RequireSession()
+	identityProviders := service.NewIdentityProviders(r.cfg)
 
 	engine.GET("/healthz", infraHandler.NewHealthCheckHandler(r.log, r.db, r.rdb))
 	engine.HEAD("/healthz", infraHandler.NewHealthCheckHandler(r.log, r.db, r.rdb))
@@ -24,28 +33,65 @@ func (r *Router) setupRoutes(engine *gin.Engine) {
 
 	auth := v1.Group("/auth")
 	{
-		auth.GET("/login/:provider", authHandler.NewLoginHandler(r.cfg, r.log))
-		auth.GET("/callback/:provider", authHandler.NewCallbackHandler(repo, r.cfg, r.log))
-		auth.POST("/refresh", authHandler.NewRefreshHandler(repo, r.log))
+		auth.GET("/login/:provider", authHandler.NewLoginHandler(r.cfg, identityProviders, r.rdb, r.log))
+		auth.GET("/callback/:provider", authHandler.NewCallbackHandler(store, r.rdb, r.cfg, identityProviders, r.log))
+		auth.GET("/device", authHandler.NewDeviceVerificationHandler(identityProviders))
+		auth.POST("/device/code", authHandler.NewDeviceCodeHandler(r.rdb, r.cfg, r.log))
+		auth.POST("/device/token", authHandler.NewDeviceTokenHandler(store, r.rdb, r.cfg, r.log))
+		auth.POST("/link/confirm", authHandler.NewConfirmLinkHandler(store, r.rdb, r.log))
+		auth.POST("/refresh", authHandler.NewRefreshHandler(store, r.rdb, r.cfg, r.log))
+		auth.POST("/logout", requireAuth, requireSession, authHandler.NewLogoutHandler(store, r.rdb, r.cfg, r.log))
+
+		sessions := auth.Group("/sessions", requireAuth, requireSession)
+		{
+			sessions.GET("", sessionHandler.NewListSessionsHandler(store, r.log))
+			sessions.DELETE("", sessionHandler.NewDeleteAllSessionsHandler(store, r.rdb, r.cfg, r.log))
+			sessions.DELETE("/:sessionId", sessionHandler.NewDeleteSessionHandler(store, r.rdb, r.cfg, r.log))
+		}
+
+		identities := auth.Group("/identities", requireAuth, requireSession)
+		{
+			identities.GET("", identityHandler.NewListIdentitiesHandler(store, r.log))
+			identities.POST("/:provider", identityHandler.NewLinkIdentityHandler(r.cfg, identityProviders, r.rdb, r.log))
+			identities.DELETE("/:provider", identityHandler.NewUnlinkIdentityHandler(store, r.log))
+		}
+
+		tokens := auth.Group("/tokens", requireAuth, requireSession)
+		{
+			tokens.POST("", tokenHandler.NewCreateTokenHandler(store, r.log))
+			tokens.GET("", tokenHandler.NewListTokensHandler(store, r.log))
+			tokens.DELETE("/:tokenId", tokenHandler.NewRevokeTokenHandler(store, r.log))
+		}
+	}
+
+	me := v1.Group("/me", requireAuth, requireSession)
+	{
+		me.GET("", accountHandler.NewGetAccountHandler(store, r.log))
+		me.DELETE("", accountHandler.NewDeleteAccountHandler(store, r.rdb, r.cfg, r.log))
 	}
 
-	rooms := v1.Group("/rooms")
+	readRooms := middleware.RequireScope(service.ScopeRoomsRead)
+	writeRooms := middleware.RequireScope(service.ScopeRoomsWrite)
+	readSecrets := middleware.RequireScope(service.ScopeSecretsRead)
+	writeSecrets := middleware.RequireScope(service.ScopeSecretsWrite)
+
+	rooms := v1.Group("/rooms", requireAuth)
 	{
-		rooms.POST("", roomHandler.NewCreateRoomHandler(repo, r.log))
-		rooms.GET("", roomHandler.NewListRoomsHandler(repo, r.log))
+		rooms.POST("", writeRooms, roomHandler.NewCreateRoomHandler(store, r.encryptor, r.log))
+		rooms.GET("", readRooms, roomHandler.NewListRoomsHandler(store, r.log))
 
 		roomID := rooms.Group("/:id")
 		{
-			roomID.GET("", roomHandler.NewGetRoomHandler(repo, r.log))
-			roomID.DELETE("", roomHandler.NewDeleteRoomHandler(repo, r.log))
-			roomID.POST("/join", roomHandler.NewJoinRoomHandler(repo, r.log))
-			roomID.POST("/leave", roomHandler.NewLeaveRoomHandler(repo, r.log))
+			roomID.GET("", readRooms, roomHandler.NewGetRoomHandler(store, r.log))
+			roomID.DELETE("", writeRooms, roomHandler.NewDeleteRoomHandler(store, r.log))
+			roomID.POST("/join", writeRooms, roomHandler.NewJoinRoomHandler(store, r.log))
+			roomID.POST("/leave", writeRooms, roomHandler.NewLeaveRoomHandler(store, r.log))
 
 			secrets := roomID.Group("/secrets")
 			{
-				secrets.POST("", secretHandler.NewCreateSecretHandler(repo, r.log))
-				secrets.GET("", secretHandler.NewListSecretsHandler(repo, r.log))
-				secrets.GET("/:secretId", secretHandler.NewGetSecretHandler(repo, r.log))
+				secrets.POST("", writeSecrets, secretHandler.NewCreateSecretHandler(store, r.encryptor, r.log))
+				secrets.GET("", readSecrets, secretHandler.NewListSecretsHandler(store, r.log))
+				secrets.GET("/:secretId", readSecrets, secretHandler.NewGetSecretHandler(store, r.encryptor, r.log))
 			}
 		}
 	}
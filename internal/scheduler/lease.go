@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	leaseKeyPrefix = "scheduler:lease:"
+	fenceKeyPrefix = "scheduler:fence:"
+)
+
+// ErrLeaseHeld is returned by AcquireLease when another instance holds the lease.
+var ErrLeaseHeld = errors.New("lease is held by another instance")
+
+// acquireScript takes the lease only when it is free and hands out a fencing token that is
+// strictly greater than any token issued before for the same lease.
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// renewScript extends the lease only while it is still owned by the caller.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lease only while it is still owned by the caller.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lease is a time-bound distributed lock held in Redis. The Token is a fencing token: each new
+// holder gets a higher value, so downstream writes can reject a holder whose lease has lapsed.
+type Lease struct {
+	rdb   *redis.Client
+	key   string
+	owner string
+	ttl   time.Duration
+	Token int64
+}
+
+// AcquireLease tries to take the named lease for owner, returning ErrLeaseHeld when it is taken.
+func AcquireLease(
+	ctx context.Context,
+	rdb *redis.Client,
+	name string,
+	owner string,
+	ttl time.Duration,
+) (*Lease, error) {
+	token, err := acquireScript.Run(ctx, rdb,
+		[]string{leaseKeyPrefix + name, fenceKeyPrefix + name},
+		owner, ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return nil, err
+	}
+	if token == 0 {
+		return nil, ErrLeaseHeld
+	}
+
+	return &Lease{
+		rdb:   rdb,
+		key:   leaseKeyPrefix + name,
+		owner: owner,
+		ttl:   ttl,
+		Token: token,
+	}, nil
+}
+
+// Renew extends the lease by its TTL. It reports false when the lease has expired or been
+// taken over, in which case the caller must stop acting as its holder.
+func (l *Lease) Renew(ctx context.Context) (bool, error) {
+	renewed, err := renewScript.Run(ctx, l.rdb, []string{l.key}, l.owner, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	return renewed == 1, nil
+}
+
+// Release gives the lease up so another instance can take it without waiting for it to expire.
+func (l *Lease) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.rdb, []string{l.key}, l.owner).Err()
+}
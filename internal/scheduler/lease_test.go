@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return m, rdb
+}
+
+func acquire(t *testing.T, rdb *redis.Client, owner string, ttl time.Duration) *Lease {
+	t.Helper()
+
+	lease, err := AcquireLease(context.Background(), rdb, "job", owner, ttl)
+	if err != nil {
+		t.Fatalf("AcquireLease(%s): %v", owner, err)
+	}
+
+	return lease
+}
+
+func renew(t *testing.T, lease *Lease) bool {
+	t.Helper()
+
+	renewed, err := lease.Renew(context.Background())
+	if err != nil {
+		t.Fatalf("Renew(%s): %v", lease.owner, err)
+	}
+
+	return renewed
+}
+
+func TestAcquireLeaseExclusive(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	ttl := time.Minute
+
+	first := acquire(t, rdb, "a", ttl)
+	if first.Token != 1 {
+		t.Fatalf("first token = %d, want 1", first.Token)
+	}
+
+	if _, err := AcquireLease(context.Background(), rdb, "job", "b", ttl); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("competing AcquireLease error = %v, want %v", err, ErrLeaseHeld)
+	}
+	if _, err := AcquireLease(context.Background(), rdb, "job", "a", ttl); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("re-entrant AcquireLease error = %v, want %v", err, ErrLeaseHeld)
+	}
+
+	other, err := AcquireLease(context.Background(), rdb, "other-job", "b", ttl)
+	if err != nil {
+		t.Fatalf("AcquireLease of another job: %v", err)
+	}
+	if other.Token != 1 {
+		t.Fatalf("other job token = %d, want 1", other.Token)
+	}
+}
+
+func TestLeaseFailover(t *testing.T) {
+	m, rdb := newTestRedis(t)
+	ttl := 30 * time.Second
+
+	old := acquire(t, rdb, "a", ttl)
+
+	m.FastForward(ttl / 2)
+	if !renew(t, old) {
+		t.Fatalf("holder could not renew a live lease")
+	}
+	m.FastForward(ttl / 2)
+	if _, err := AcquireLease(context.Background(), rdb, "job", "b", ttl); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("renewed lease was taken over: %v", err)
+	}
+
+	m.FastForward(ttl)
+	successor := acquire(t, rdb, "b", ttl)
+	if successor.Token <= old.Token {
+		t.Fatalf("successor token = %d, want greater than %d", successor.Token, old.Token)
+	}
+
+	if renew(t, old) {
+		t.Fatalf("lapsed holder renewed a lease taken over by another owner")
+	}
+	if err := old.Release(context.Background()); err != nil {
+		t.Fatalf("Release by lapsed holder: %v", err)
+	}
+	if got, _ := m.Get(leaseKeyPrefix + "job"); got != "b" {
+		t.Fatalf("lease owner after release by lapsed holder = %q, want b", got)
+	}
+	if ttlLeft := m.TTL(leaseKeyPrefix + "job"); ttlLeft != ttl {
+		t.Fatalf("successor lease TTL = %v, want %v", ttlLeft, ttl)
+	}
+
+	if !renew(t, successor) {
+		t.Fatalf("successor could not renew its lease")
+	}
+	if err := successor.Release(context.Background()); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	next := acquire(t, rdb, "a", ttl)
+	if next.Token <= successor.Token {
+		t.Fatalf("token after release = %d, want greater than %d", next.Token, successor.Token)
+	}
+}
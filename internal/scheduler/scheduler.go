@@ -0,0 +1,184 @@
+// Package scheduler runs periodic background jobs on exactly one API instance at a time,
+// using Redis leases for leader election and fail-over.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLeaseTTL = 30 * time.Second
+	releaseTimeout  = 2 * time.Second
+)
+
+type fenceKey struct{}
+
+// Fence identifies the lease a job runs under. Writes made by the job should be guarded by it,
+// see repository.Store.ExecFenced.
+type Fence struct {
+	Job   string
+	Token int64
+}
+
+// Job is a periodic task. Run is called every Interval while this instance holds the job's lease,
+// and its context is cancelled as soon as the lease is lost.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context)
+}
+
+// Scheduler elects a leader per job among every instance sharing the same Redis.
+type Scheduler struct {
+	rdb        *redis.Client
+	log        *zap.Logger
+	instanceID string
+	leaseTTL   time.Duration
+	jobs       []Job
+	wg         sync.WaitGroup
+}
+
+// New creates a new Scheduler. A crashed leader is replaced after at most leaseTTL.
+func New(rdb *redis.Client, log *zap.Logger, leaseTTL time.Duration) *Scheduler {
+	if leaseTTL <= 0 {
+		leaseTTL = defaultLeaseTTL
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &Scheduler{
+		rdb:        rdb,
+		log:        log,
+		instanceID: fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		leaseTTL:   leaseTTL,
+	}
+}
+
+// FencingToken returns the fence of the lease under which the job is running. It reports false
+// outside of a scheduled job, e.g. when a job is run by hand from the command line.
+func FencingToken(ctx context.Context) (Fence, bool) {
+	fence, ok := ctx.Value(fenceKey{}).(Fence)
+	return fence, ok
+}
+
+// Register adds a job. Jobs with a non-positive interval are disabled.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start begins competing for every registered job until the context is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			s.log.Info("Scheduled job disabled", zap.String("job", job.Name))
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.compete(ctx, job)
+		}()
+	}
+}
+
+// Wait blocks until every job has stopped after the context passed to Start was cancelled.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// compete repeatedly tries to take the job's lease and runs the job while holding it.
+func (s *Scheduler) compete(ctx context.Context, job Job) {
+	retry := time.NewTicker(s.leaseTTL / 3)
+	defer retry.Stop()
+
+	for {
+		lease, err := AcquireLease(ctx, s.rdb, job.Name, s.instanceID, s.leaseTTL)
+		switch {
+		case err == nil:
+			s.lead(ctx, job, lease)
+		case !errors.Is(err, ErrLeaseHeld) && ctx.Err() == nil:
+			s.log.Error("Failed to acquire job lease", zap.String("job", job.Name), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-retry.C:
+		}
+	}
+}
+
+// lead runs the job while renewing its lease, stopping the job once the lease cannot be renewed.
+func (s *Scheduler) lead(ctx context.Context, job Job, lease *Lease) {
+	s.log.Info("Acquired job lease",
+		zap.String("job", job.Name),
+		zap.Int64("fencing_token", lease.Token),
+	)
+
+	jobCtx, cancel := context.WithCancel(context.WithValue(ctx, fenceKey{}, Fence{Job: job.Name, Token: lease.Token}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runEvery(jobCtx, job.Interval, job.Run)
+	}()
+
+	defer func() {
+		cancel()
+		<-done
+
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancelRelease()
+		if err := lease.Release(releaseCtx); err != nil {
+			s.log.Warn("Failed to release job lease", zap.String("job", job.Name), zap.Error(err))
+		}
+	}()
+
+	renew := time.NewTicker(s.leaseTTL / 3)
+	defer renew.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-renew.C:
+			renewed, err := lease.Renew(ctx)
+			if err != nil || !renewed {
+				s.log.Warn("Lost job lease",
+					zap.String("job", job.Name),
+					zap.Int64("fencing_token", lease.Token),
+					zap.Error(err),
+				)
+				return
+			}
+		}
+	}
+}
+
+// runEvery calls fn immediately and then on every tick until the context is cancelled.
+func runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
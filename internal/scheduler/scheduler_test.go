@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fenceRecorder records the fences the job ran under, per scheduler instance.
+type fenceRecorder struct {
+	mu   sync.Mutex
+	runs map[string][]Fence
+}
+
+func (r *fenceRecorder) job(instance string) Job {
+	return Job{
+		Name:     "job",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) {
+			fence, ok := FencingToken(ctx)
+			if !ok {
+				return
+			}
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.runs[instance] = append(r.runs[instance], fence)
+		},
+	}
+}
+
+func (r *fenceRecorder) count(instance string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.runs[instance])
+}
+
+func (r *fenceRecorder) fences(instance string) []Fence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Fence(nil), r.runs[instance]...)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSchedulerSingleLeaderAndFailover(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	recorder := &fenceRecorder{runs: map[string][]Fence{}}
+	leaseTTL := 60 * time.Millisecond
+
+	first := New(rdb, zap.NewNop(), leaseTTL)
+	first.Register(recorder.job("first"))
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	first.Start(firstCtx)
+	defer func() {
+		stopFirst()
+		first.Wait()
+	}()
+
+	waitFor(t, "the first instance to lead", func() bool { return recorder.count("first") >= 3 })
+
+	second := New(rdb, zap.NewNop(), leaseTTL)
+	second.Register(recorder.job("second"))
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	second.Start(secondCtx)
+	defer func() {
+		stopSecond()
+		second.Wait()
+	}()
+
+	// Give the second instance several lease retries while the first keeps renewing.
+	time.Sleep(3 * leaseTTL)
+	if n := recorder.count("second"); n != 0 {
+		t.Fatalf("second instance ran %d times while the first held the lease", n)
+	}
+
+	stopFirst()
+	first.Wait()
+	waitFor(t, "the second instance to take over", func() bool { return recorder.count("second") > 0 })
+
+	firstFences := recorder.fences("first")
+	leaderToken := firstFences[0].Token
+	for _, fence := range firstFences {
+		if fence.Job != "job" || fence.Token != leaderToken {
+			t.Fatalf("first instance ran under %+v, want job %q with token %d", fence, "job", leaderToken)
+		}
+	}
+	if fence := recorder.fences("second")[0]; fence.Token <= leaderToken {
+		t.Fatalf("second instance token = %d, want greater than %d", fence.Token, leaderToken)
+	}
+}
+
+func TestFencingTokenOutsideScheduler(t *testing.T) {
+	if fence, ok := FencingToken(context.Background()); ok {
+		t.Fatalf("FencingToken outside a scheduled job = %+v, want none", fence)
+	}
+}
+
+func TestSchedulerSkipsDisabledJobs(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	ran := make(chan struct{}, 1)
+
+	s := New(rdb, zap.NewNop(), time.Minute)
+	s.Register(Job{Name: "disabled", Run: func(context.Context) { ran <- struct{}{} }})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	cancel()
+	s.Wait()
+
+	select {
+	case <-ran:
+		t.Fatalf("job with a zero interval ran")
+	default:
+	}
+}
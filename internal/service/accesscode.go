@@ -0,0 +1,74 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters used for room access codes, following the OWASP baseline recommendation.
+const (
+	argon2Time    uint32 = 3
+	argon2Memory  uint32 = 64 * 1024
+	argon2Threads uint8  = 2
+	argon2KeyLen  uint32 = 32
+	argon2SaltLen        = 16
+)
+
+// ErrInvalidAccessCodeHash is returned when a stored access code hash cannot be decoded.
+var ErrInvalidAccessCodeHash = errors.New("invalid access code hash")
+
+// HashAccessCode derives an argon2id hash of the room access code and returns it in PHC string format.
+func HashAccessCode(code string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash := argon2.IDKey([]byte(code), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// VerifyAccessCode reports whether the access code matches the PHC encoded argon2id hash.
+func VerifyAccessCode(code string, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ErrInvalidAccessCodeHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrInvalidAccessCodeHash
+	}
+
+	var memory, iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil ||
+		iterations == 0 || threads == 0 {
+		return false, ErrInvalidAccessCodeHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrInvalidAccessCodeHash
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(expected) != int(argon2KeyLen) {
+		return false, ErrInvalidAccessCodeHash
+	}
+
+	actual := argon2.IDKey([]byte(code), salt, iterations, memory, threads, argon2KeyLen)
+
+	return subtle.ConstantTimeCompare(actual, expected) == 1, nil
+}
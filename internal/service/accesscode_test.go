@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifyAccessCode(t *testing.T) {
+	encoded, err := HashAccessCode("correct horse")
+	if err != nil {
+		t.Fatalf("HashAccessCode: %v", err)
+	}
+	parts := strings.Split(encoded, "$")
+
+	tests := []struct {
+		name    string
+		code    string
+		encoded string
+		want    bool
+		wantErr error
+	}{
+		{name: "matching code", code: "correct horse", encoded: encoded, want: true},
+		{name: "wrong code", code: "battery staple", encoded: encoded},
+		{name: "empty code", code: "", encoded: encoded},
+		{name: "empty hash", code: "correct horse", encoded: "", wantErr: ErrInvalidAccessCodeHash},
+		{
+			name:    "other algorithm",
+			code:    "correct horse",
+			encoded: strings.Replace(encoded, "$argon2id$", "$argon2i$", 1),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+		{
+			name:    "other version",
+			code:    "correct horse",
+			encoded: strings.Replace(encoded, "$v=19$", "$v=16$", 1),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+		{
+			name:    "malformed parameters",
+			code:    "correct horse",
+			encoded: strings.Join([]string{"", parts[1], parts[2], "m=x", parts[4], parts[5]}, "$"),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+		{
+			name:    "zero parallelism",
+			code:    "correct horse",
+			encoded: strings.Join([]string{"", parts[1], parts[2], "m=65536,t=3,p=0", parts[4], parts[5]}, "$"),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+		{
+			name:    "invalid salt",
+			code:    "correct horse",
+			encoded: strings.Join([]string{"", parts[1], parts[2], parts[3], "!!", parts[5]}, "$"),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+		{
+			name:    "truncated hash",
+			code:    "correct horse",
+			encoded: strings.Join([]string{"", parts[1], parts[2], parts[3], parts[4], parts[5][:10]}, "$"),
+			wantErr: ErrInvalidAccessCodeHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyAccessCode(tt.code, tt.encoded)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyAccessCode error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("VerifyAccessCode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashAccessCodeSalted(t *testing.T) {
+	first, err := HashAccessCode("correct horse")
+	if err != nil {
+		t.Fatalf("HashAccessCode: %v", err)
+	}
+	second, err := HashAccessCode("correct horse")
+	if err != nil {
+		t.Fatalf("HashAccessCode: %v", err)
+	}
+
+	if first == second {
+		t.Fatalf("hashing the same code twice produced the same hash")
+	}
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Fatalf("hash %q is not an argon2id PHC string with the configured parameters", first)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrIdentityLinked is returned when a provider identity already belongs to another user.
+	ErrIdentityLinked = errors.New("identity is linked to another user")
+	// ErrProviderLinked is returned when the user already has an identity with the provider.
+	ErrProviderLinked = errors.New("provider is already linked")
+	// ErrIdentityNotFound is returned when the user has no identity with the provider.
+	ErrIdentityNotFound = errors.New("identity not found")
+	// ErrLastIdentity is returned when unlinking would leave the user unable to sign in.
+	ErrLastIdentity = errors.New("cannot unlink the last identity")
+)
+
+// ResolveUser returns the user the provider identity belongs to. On the first login with an
+// identity, a new user owning it is created.
+func ResolveUser(
+	ctx context.Context,
+	store repository.Store,
+	provider string,
+	userInfo *dto.UserInfoResponseDto,
+) (repository.User, error) {
+	var user repository.User
+	err := store.ExecTx(ctx, func(q repository.Querier) error {
+		var err error
+		user, err = q.GetUserByIdentity(ctx, repository.GetUserByIdentityParams{
+			Provider:   provider,
+			ProviderID: userInfo.ID,
+		})
+		if err == nil {
+			return syncIdentityEmail(ctx, q, user.ID, provider, userInfo)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		user, err = q.CreateUser(ctx, repository.CreateUserParams{
+			Email:         userEmail(userInfo),
+			EmailVerified: userInfo.EmailVerified,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+			UserID:        user.ID,
+			Provider:      provider,
+			ProviderID:    userInfo.ID,
+			Email:         userEmail(userInfo),
+			EmailVerified: userInfo.EmailVerified,
+		})
+		return err
+	})
+
+	return user, err
+}
+
+// LinkIdentity attaches the provider identity to the user, so either identity signs in to the
+// same account. Linking an identity the user already owns is a no-op.
+func LinkIdentity(
+	ctx context.Context,
+	store repository.Store,
+	userID uuid.UUID,
+	provider string,
+	userInfo *dto.UserInfoResponseDto,
+) (repository.UserIdentity, error) {
+	var identity repository.UserIdentity
+	err := store.ExecTx(ctx, func(q repository.Querier) error {
+		var err error
+		identity, err = q.GetUserIdentity(ctx, repository.GetUserIdentityParams{
+			Provider:   provider,
+			ProviderID: userInfo.ID,
+		})
+		if err == nil {
+			if identity.UserID != userID {
+				return ErrIdentityLinked
+			}
+			return syncIdentityEmail(ctx, q, userID, provider, userInfo)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		identity, err = q.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+			UserID:        userID,
+			Provider:      provider,
+			ProviderID:    userInfo.ID,
+			Email:         userEmail(userInfo),
+			EmailVerified: userInfo.EmailVerified,
+		})
+		if repository.IsUniqueViolation(err) {
+			return ErrProviderLinked
+		}
+		if err != nil {
+			return err
+		}
+
+		return promoteVerifiedEmail(ctx, q, userID, userInfo)
+	})
+
+	return identity, err
+}
+
+// UnlinkIdentity removes the user's identity with the provider, refusing to remove the last one.
+func UnlinkIdentity(ctx context.Context, store repository.Store, userID uuid.UUID, provider string) error {
+	return store.ExecTx(ctx, func(q repository.Querier) error {
+		identities, err := q.ListUserIdentities(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, identity := range identities {
+			found = found || identity.Provider == provider
+		}
+		if !found {
+			return ErrIdentityNotFound
+		}
+		if len(identities) == 1 {
+			return ErrLastIdentity
+		}
+
+		_, err = q.DeleteUserIdentity(ctx, repository.DeleteUserIdentityParams{
+			UserID:   userID,
+			Provider: provider,
+		})
+		return err
+	})
+}
+
+// syncIdentityEmail records the email the provider currently reports for the identity, since
+// users may change or verify their address after the first login.
+func syncIdentityEmail(
+	ctx context.Context,
+	q repository.Querier,
+	userID uuid.UUID,
+	provider string,
+	userInfo *dto.UserInfoResponseDto,
+) error {
+	if err := q.UpdateUserIdentityEmail(ctx, repository.UpdateUserIdentityEmailParams{
+		Provider:      provider,
+		ProviderID:    userInfo.ID,
+		Email:         userEmail(userInfo),
+		EmailVerified: userInfo.EmailVerified,
+	}); err != nil {
+		return err
+	}
+
+	return promoteVerifiedEmail(ctx, q, userID, userInfo)
+}
+
+// promoteVerifiedEmail makes a verified provider email the user's email when the user has none
+// or only an unverified one. A verified email is never replaced.
+func promoteVerifiedEmail(
+	ctx context.Context,
+	q repository.Querier,
+	userID uuid.UUID,
+	userInfo *dto.UserInfoResponseDto,
+) error {
+	if !userInfo.EmailVerified || userInfo.Email == "" {
+		return nil
+	}
+
+	return q.SetVerifiedUserEmail(ctx, repository.SetVerifiedUserEmailParams{
+		ID:    userID,
+		Email: userEmail(userInfo),
+	})
+}
+
+func userEmail(userInfo *dto.UserInfoResponseDto) pgtype.Text {
+	return pgtype.Text{String: userInfo.Email, Valid: userInfo.Email != ""}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+)
+
+const authFlowKeyPrefix = "oauth:state:"
+
+// ErrAuthFlowNotFound is returned when a state value is unknown, expired or already used.
+var ErrAuthFlowNotFound = errors.New("authorization request not found")
+
+// AuthFlow holds the secrets bound to a single authorization request: the CSRF state, the PKCE
+// code verifier whose S256 challenge is sent with the request, and the OIDC nonce expected in
+// the returned ID token. It is kept server side, keyed by state, so the login can be completed
+// from any browser. UserCode is set when the login approves a device authorization request
+// instead of issuing tokens to the browser, and LinkUser when it links the identity to an
+// existing user.
+type AuthFlow struct {
+	State     string    `json:"-"`
+	Provider  string    `json:"provider"`
+	Verifier  string    `json:"verifier"`
+	Nonce     string    `json:"nonce"`
+	Redirect  string    `json:"redirect,omitempty"`
+	UserCode  string    `json:"user_code,omitempty"`
+	LinkUser  uuid.UUID `json:"link_user,omitzero"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewAuthFlow generates fresh secrets for a new authorization request with the provider.
+// Redirect is where the user is sent with their tokens once the login completes, if anywhere.
+func NewAuthFlow(provider string, redirect string, ttl time.Duration) (*AuthFlow, error) {
+	state, err := GenerateRandomState()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := GenerateRandomState()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthFlow{
+		State:     state,
+		Provider:  provider,
+		Verifier:  oauth2.GenerateVerifier(),
+		Nonce:     nonce,
+		Redirect:  redirect,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// SaveAuthFlow stores the flow in Redis until it expires.
+func SaveAuthFlow(ctx context.Context, rdb *redis.Client, flow *AuthFlow) error {
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return err
+	}
+
+	return rdb.Set(ctx, authFlowKeyPrefix+flow.State, data, time.Until(flow.ExpiresAt)).Err()
+}
+
+// ConsumeAuthFlow atomically fetches and deletes the flow identified by state, so each
+// authorization response can be redeemed only once.
+func ConsumeAuthFlow(ctx context.Context, rdb *redis.Client, state string) (*AuthFlow, error) {
+	data, err := rdb.GetDel(ctx, authFlowKeyPrefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrAuthFlowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var flow AuthFlow
+	if err := json.Unmarshal(data, &flow); err != nil {
+		return nil, err
+	}
+	if time.Now().After(flow.ExpiresAt) {
+		return nil, ErrAuthFlowNotFound
+	}
+	flow.State = state
+
+	return &flow, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const accountDeletionKeyPrefix = "account:delete:"
+
+// Policies for the rooms owned by a user deleting their account.
+const (
+	// RoomPolicyTransfer hands each owned room to its longest standing member with the highest
+	// role, who becomes an admin. Rooms without other members are deleted.
+	RoomPolicyTransfer = "transfer"
+	// RoomPolicyDelete deletes every owned room together with its secrets.
+	RoomPolicyDelete = "delete"
+)
+
+var (
+	// ErrDeletionNotConfirmed is returned when an account deletion is attempted with a confirmation
+	// token that is unknown, expired, already used or issued for another room policy.
+	ErrDeletionNotConfirmed = errors.New("account deletion not confirmed")
+	// ErrUserNotFound is returned when the user no longer exists.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+type accountDeletion struct {
+	TokenHash  string `json:"token_hash"`
+	RoomPolicy string `json:"room_policy"`
+}
+
+// AccountDeletionResult describes what happened to the rooms of a deleted account.
+type AccountDeletionResult struct {
+	RoomsTransferred int64
+	RoomsDeleted     int64
+}
+
+// RequestAccountDeletion issues the confirmation token the user must send back to delete their
+// account with the given room policy. Requesting again replaces any previous token.
+func RequestAccountDeletion(
+	ctx context.Context,
+	rdb *redis.Client,
+	userID uuid.UUID,
+	roomPolicy string,
+	ttl time.Duration,
+) (string, time.Time, error) {
+	token, err := GenerateRandomState()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	data, err := json.Marshal(accountDeletion{
+		TokenHash:  hashOpaqueToken(token),
+		RoomPolicy: roomPolicy,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := rdb.Set(ctx, accountDeletionKeyPrefix+userID.String(), data, ttl).Err(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, time.Now().Add(ttl), nil
+}
+
+// ConfirmAccountDeletion consumes the confirmation token, so it can be used only once.
+func ConfirmAccountDeletion(
+	ctx context.Context,
+	rdb *redis.Client,
+	userID uuid.UUID,
+	token string,
+	roomPolicy string,
+) error {
+	data, err := rdb.GetDel(ctx, accountDeletionKeyPrefix+userID.String()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ErrDeletionNotConfirmed
+	}
+	if err != nil {
+		return err
+	}
+
+	var pending accountDeletion
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(pending.TokenHash), []byte(hashOpaqueToken(token))) != 1 ||
+		pending.RoomPolicy != roomPolicy {
+		return ErrDeletionNotConfirmed
+	}
+
+	return nil
+}
+
+// DeleteAccount deletes the user and, through ON DELETE CASCADE, their identities, sessions,
+// memberships, secrets and the rooms they still own once roomPolicy has been applied. Access
+// tokens of the user are revoked before the deletion is committed, so a failed revocation leaves
+// the account in place to be deleted again rather than deleted with live tokens.
+func DeleteAccount(
+	ctx context.Context,
+	store repository.Store,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	userID uuid.UUID,
+	roomPolicy string,
+) (AccountDeletionResult, error) {
+	if err := RevokeUserTokens(ctx, rdb, userID, time.Hour*time.Duration(cfg.JWTExpirationHours)); err != nil {
+		return AccountDeletionResult{}, err
+	}
+
+	var result AccountDeletionResult
+	err := store.ExecTx(ctx, func(q repository.Querier) error {
+		var err error
+		result = AccountDeletionResult{}
+
+		if roomPolicy == RoomPolicyTransfer {
+			result.RoomsTransferred, err = q.TransferOwnedRooms(ctx, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		counts, err := q.CountUserRooms(ctx, userID)
+		if err != nil {
+			return err
+		}
+		result.RoomsDeleted = counts.OwnedRooms
+
+		deleted, err := q.DeleteUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrUserNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return AccountDeletionResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// deletionStore records whether DeleteAccount got as far as deleting the user.
+type deletionStore struct {
+	repository.Store
+
+	deleted bool
+}
+
+func (s *deletionStore) ExecTx(_ context.Context, fn func(repository.Querier) error) error {
+	return fn(s)
+}
+
+func (s *deletionStore) CountUserRooms(context.Context, uuid.UUID) (repository.CountUserRoomsRow, error) {
+	return repository.CountUserRoomsRow{}, nil
+}
+
+func (s *deletionStore) DeleteUser(context.Context, uuid.UUID) (int64, error) {
+	s.deleted = true
+	return 1, nil
+}
+
+func TestDeleteAccountRevokesTokensFirst(t *testing.T) {
+	ctx := context.Background()
+	cfg := &configs.Conf{JWTExpirationHours: 1}
+	claims := &TokenClaims{ID: "jti", UserID: uuid.New(), SessionID: uuid.New(), IssuedAt: time.Now()}
+
+	t.Run("redis unavailable", func(t *testing.T) {
+		mr, rdb := newTestRedis(t)
+		mr.Close()
+
+		store := &deletionStore{}
+		if _, err := DeleteAccount(ctx, store, rdb, cfg, claims.UserID, RoomPolicyDelete); err == nil {
+			t.Fatalf("DeleteAccount succeeded without revoking tokens")
+		}
+		if store.deleted {
+			t.Fatalf("account deleted although its tokens could not be revoked")
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		_, rdb := newTestRedis(t)
+
+		store := &deletionStore{}
+		if _, err := DeleteAccount(ctx, store, rdb, cfg, claims.UserID, RoomPolicyDelete); err != nil {
+			t.Fatalf("DeleteAccount: %v", err)
+		}
+		if !store.deleted {
+			t.Fatalf("account not deleted")
+		}
+
+		revoked, err := IsTokenRevoked(ctx, rdb, claims)
+		if err != nil {
+			t.Fatalf("IsTokenRevoked: %v", err)
+		}
+		if !revoked {
+			t.Fatalf("access token of the deleted account is still accepted")
+		}
+	})
+}
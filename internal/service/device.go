@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	deviceCodeKeyPrefix = "device:code:"
+	deviceUserKeyPrefix = "device:user:"
+	devicePollKeyPrefix = "device:poll:"
+
+	// userCodeAlphabet avoids vowels and look-alike characters, as suggested by RFC 8628.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+// Device authorization states.
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+)
+
+var (
+	// ErrDeviceCodeNotFound is returned when a device or user code is unknown, expired or already redeemed.
+	ErrDeviceCodeNotFound = errors.New("device code not found")
+	// ErrDeviceAuthorizationPending is returned while the user has not completed the login yet.
+	ErrDeviceAuthorizationPending = errors.New("device authorization pending")
+	// ErrDeviceSlowDown is returned when the device polls faster than the advertised interval.
+	ErrDeviceSlowDown = errors.New("device polling too fast")
+)
+
+// DeviceAuthorization is a pending OAuth 2.0 device authorization grant (RFC 8628).
+type DeviceAuthorization struct {
+	UserCode  string    `json:"user_code"`
+	Status    string    `json:"status"`
+	UserID    uuid.UUID `json:"user_id,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateDeviceAuthorization starts a device authorization and returns the device code kept by
+// the polling client together with the user code the user enters on a second device.
+func CreateDeviceAuthorization(
+	ctx context.Context,
+	rdb *redis.Client,
+	ttl time.Duration,
+) (string, *DeviceAuthorization, error) {
+	deviceCode, err := GenerateRandomState()
+	if err != nil {
+		return "", nil, err
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		return "", nil, err
+	}
+
+	auth := &DeviceAuthorization{
+		UserCode:  userCode,
+		Status:    DeviceAuthorizationPending,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return "", nil, err
+	}
+
+	codeKey := deviceCodeKeyPrefix + hashOpaqueToken(deviceCode)
+	ok, err := rdb.SetNX(ctx, deviceUserKeyPrefix+userCode, codeKey, ttl).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		return "", nil, errors.New("user code collision")
+	}
+
+	if err := rdb.Set(ctx, codeKey, data, ttl).Err(); err != nil {
+		return "", nil, err
+	}
+
+	return deviceCode, auth, nil
+}
+
+// NormalizeUserCode upper-cases a user code and strips the separators users may type.
+func NormalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
+
+// FormatUserCode renders a user code as two dash separated groups for display.
+func FormatUserCode(userCode string) string {
+	if len(userCode) != userCodeLength {
+		return userCode
+	}
+
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
+
+// CheckUserCode reports ErrDeviceCodeNotFound unless the user code belongs to a pending authorization.
+func CheckUserCode(ctx context.Context, rdb *redis.Client, userCode string) error {
+	_, auth, err := loadDeviceAuthorizationByUserCode(ctx, rdb, userCode)
+	if err != nil {
+		return err
+	}
+	if auth.Status != DeviceAuthorizationPending {
+		return ErrDeviceCodeNotFound
+	}
+
+	return nil
+}
+
+// ApproveDeviceAuthorization records that the user identified by userID completed the login
+// for the device showing userCode. The authorization is updated optimistically under WATCH, so
+// of two concurrent approvals only one succeeds.
+func ApproveDeviceAuthorization(
+	ctx context.Context,
+	rdb *redis.Client,
+	userCode string,
+	userID uuid.UUID,
+	provider string,
+) error {
+	userKey := deviceUserKeyPrefix + NormalizeUserCode(userCode)
+	codeKey, err := rdb.Get(ctx, userKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = rdb.Watch(ctx, func(tx *redis.Tx) error {
+		auth, err := loadDeviceAuthorization(ctx, tx, codeKey)
+		if err != nil {
+			return err
+		}
+		if auth.Status != DeviceAuthorizationPending {
+			return ErrDeviceCodeNotFound
+		}
+
+		auth.Status = DeviceAuthorizationApproved
+		auth.UserID = userID
+		auth.Provider = provider
+
+		data, err := json.Marshal(auth)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, codeKey, data, redis.SetArgs{Mode: "XX", KeepTTL: true})
+			pipe.Del(ctx, userKey)
+			return nil
+		})
+		return err
+	}, codeKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrDeviceCodeNotFound
+	}
+
+	return err
+}
+
+// PollDeviceAuthorization is called by the device with its device code. Once the user has
+// approved the request, the authorization is redeemed and deleted so tokens are issued only once;
+// if issuing them fails, the caller must put it back with RestoreDeviceAuthorization.
+// Polls are rate limited with a separate key, so polling never rewrites the authorization.
+func PollDeviceAuthorization(
+	ctx context.Context,
+	rdb *redis.Client,
+	deviceCode string,
+	interval time.Duration,
+) (*DeviceAuthorization, error) {
+	codeHash := hashOpaqueToken(deviceCode)
+	codeKey := deviceCodeKeyPrefix + codeHash
+
+	auth, err := loadDeviceAuthorization(ctx, rdb, codeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.Status == DeviceAuthorizationApproved {
+		deleted, err := rdb.Del(ctx, codeKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		if deleted == 0 {
+			return nil, ErrDeviceCodeNotFound
+		}
+		return auth, nil
+	}
+
+	// Every poll restarts the interval; finding the previous poll's marker means it came too soon.
+	err = rdb.SetArgs(ctx, devicePollKeyPrefix+codeHash, 1, redis.SetArgs{Get: true, TTL: interval}).Err()
+	if err == nil {
+		return nil, ErrDeviceSlowDown
+	}
+	if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return nil, ErrDeviceAuthorizationPending
+}
+
+// RestoreDeviceAuthorization puts back an approved authorization redeemed by
+// PollDeviceAuthorization when issuing the device's tokens failed, so the device can poll again
+// instead of losing the user's approval. Nothing is restored once the authorization has expired.
+func RestoreDeviceAuthorization(
+	ctx context.Context,
+	rdb *redis.Client,
+	deviceCode string,
+	auth *DeviceAuthorization,
+) error {
+	ttl := time.Until(auth.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+
+	return rdb.SetNX(ctx, deviceCodeKeyPrefix+hashOpaqueToken(deviceCode), data, ttl).Err()
+}
+
+func loadDeviceAuthorizationByUserCode(
+	ctx context.Context,
+	rdb *redis.Client,
+	userCode string,
+) (string, *DeviceAuthorization, error) {
+	codeKey, err := rdb.Get(ctx, deviceUserKeyPrefix+NormalizeUserCode(userCode)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	auth, err := loadDeviceAuthorization(ctx, rdb, codeKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return codeKey, auth, nil
+}
+
+func loadDeviceAuthorization(ctx context.Context, rdb redis.Cmdable, codeKey string) (*DeviceAuthorization, error) {
+	data, err := rdb.Get(ctx, codeKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrDeviceCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var auth DeviceAuthorization
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, err
+	}
+	if time.Now().After(auth.ExpiresAt) {
+		return nil, ErrDeviceCodeNotFound
+	}
+
+	return &auth, nil
+}
+
+func generateUserCode() (string, error) {
+	b := make([]byte, userCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, userCodeLength)
+	for i := range b {
+		code[i] = userCodeAlphabet[int(b[i])%len(userCodeAlphabet)]
+	}
+
+	return string(code), nil
+}
+
+// hashOpaqueToken hashes a high-entropy random token so only its digest is kept in Redis.
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
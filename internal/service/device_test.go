@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return mr, rdb
+}
+
+func TestPollDeviceAuthorizationSlowDown(t *testing.T) {
+	ctx := context.Background()
+	mr, rdb := newTestRedis(t)
+	interval := 5 * time.Second
+
+	deviceCode, _, err := CreateDeviceAuthorization(ctx, rdb, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateDeviceAuthorization: %v", err)
+	}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    error
+	}{
+		{name: "first poll", want: ErrDeviceAuthorizationPending},
+		{name: "immediate poll", want: ErrDeviceSlowDown},
+		{name: "poll before interval", advance: interval - time.Second, want: ErrDeviceSlowDown},
+		{name: "poll after interval", advance: interval, want: ErrDeviceAuthorizationPending},
+	}
+
+	for _, step := range steps {
+		mr.FastForward(step.advance)
+		if _, err := PollDeviceAuthorization(ctx, rdb, deviceCode, interval); !errors.Is(err, step.want) {
+			t.Fatalf("%s: PollDeviceAuthorization error = %v, want %v", step.name, err, step.want)
+		}
+	}
+
+	if _, err := PollDeviceAuthorization(ctx, rdb, "unknown", interval); !errors.Is(err, ErrDeviceCodeNotFound) {
+		t.Fatalf("unknown code: PollDeviceAuthorization error = %v, want %v", err, ErrDeviceCodeNotFound)
+	}
+}
+
+func TestApproveDeviceAuthorizationConcurrent(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+
+	deviceCode, auth, err := CreateDeviceAuthorization(ctx, rdb, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateDeviceAuthorization: %v", err)
+	}
+
+	const approvers = 8
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		approved []uuid.UUID
+	)
+
+	for range approvers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			userID := uuid.New()
+			err := ApproveDeviceAuthorization(ctx, rdb, FormatUserCode(auth.UserCode), userID, "google")
+			switch {
+			case err == nil:
+				mu.Lock()
+				approved = append(approved, userID)
+				mu.Unlock()
+			case !errors.Is(err, ErrDeviceCodeNotFound):
+				t.Errorf("ApproveDeviceAuthorization: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(approved) != 1 {
+		t.Fatalf("%d approvals succeeded, want exactly 1", len(approved))
+	}
+
+	redeemed, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Minute)
+	if err != nil {
+		t.Fatalf("PollDeviceAuthorization: %v", err)
+	}
+	if redeemed.UserID != approved[0] || redeemed.Status != DeviceAuthorizationApproved {
+		t.Fatalf("redeemed %+v, want approval by %s", redeemed, approved[0])
+	}
+
+	if _, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Minute); !errors.Is(err, ErrDeviceCodeNotFound) {
+		t.Fatalf("second redeem error = %v, want %v", err, ErrDeviceCodeNotFound)
+	}
+}
+
+func TestPollDoesNotOverwriteApproval(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+
+	deviceCode, auth, err := CreateDeviceAuthorization(ctx, rdb, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateDeviceAuthorization: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour)
+		}()
+	}
+
+	userID := uuid.New()
+	approveErr := ApproveDeviceAuthorization(ctx, rdb, auth.UserCode, userID, "github")
+	wg.Wait()
+	if approveErr != nil {
+		t.Fatalf("ApproveDeviceAuthorization: %v", approveErr)
+	}
+
+	redeemed, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour)
+	if err != nil {
+		t.Fatalf("PollDeviceAuthorization: %v", err)
+	}
+	if redeemed.UserID != userID {
+		t.Fatalf("redeemed user = %s, want %s", redeemed.UserID, userID)
+	}
+}
+
+func TestRestoreDeviceAuthorization(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+
+	deviceCode, auth, err := CreateDeviceAuthorization(ctx, rdb, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateDeviceAuthorization: %v", err)
+	}
+	userID := uuid.New()
+	if err := ApproveDeviceAuthorization(ctx, rdb, auth.UserCode, userID, "google"); err != nil {
+		t.Fatalf("ApproveDeviceAuthorization: %v", err)
+	}
+
+	redeemed, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour)
+	if err != nil {
+		t.Fatalf("PollDeviceAuthorization: %v", err)
+	}
+	if _, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour); !errors.Is(err, ErrDeviceCodeNotFound) {
+		t.Fatalf("second redemption error = %v, want %v", err, ErrDeviceCodeNotFound)
+	}
+
+	if err := RestoreDeviceAuthorization(ctx, rdb, deviceCode, redeemed); err != nil {
+		t.Fatalf("RestoreDeviceAuthorization: %v", err)
+	}
+
+	again, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour)
+	if err != nil {
+		t.Fatalf("PollDeviceAuthorization after restore: %v", err)
+	}
+	if again.UserID != userID || again.Status != DeviceAuthorizationApproved {
+		t.Fatalf("restored authorization = %+v, want approved for %s", again, userID)
+	}
+
+	expired := *again
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	if err := RestoreDeviceAuthorization(ctx, rdb, deviceCode, &expired); err != nil {
+		t.Fatalf("RestoreDeviceAuthorization of expired authorization: %v", err)
+	}
+	if _, err := PollDeviceAuthorization(ctx, rdb, deviceCode, time.Hour); !errors.Is(err, ErrDeviceCodeNotFound) {
+		t.Fatalf("expired restore error = %v, want %v", err, ErrDeviceCodeNotFound)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+const dataKeySize = 32
+
+const (
+	// SecretAlgorithm identifies the AEAD protecting secret contents, on the server and in clients.
+	SecretAlgorithm = "AES-256-GCM"
+	// SecretNonceSize is the nonce length in bytes expected for SecretAlgorithm.
+	SecretNonceSize = 12
+)
+
+// ErrDecryptionFailed is returned when a ciphertext cannot be authenticated with the given key and context.
+var ErrDecryptionFailed = errors.New("decryption failed")
+
+// RoomKey is a room data key wrapped by the master key of the given version.
+type RoomKey struct {
+	Wrapped []byte
+	Version int32
+}
+
+// Encryptor implements envelope encryption for secrets: every room owns a random AES-256 data key
+// (DEK) that is stored wrapped by the master key, and secrets are sealed with AES-256-GCM under
+// the room DEK. Room and secret IDs are bound as associated data so ciphertexts cannot be moved
+// between rooms or swapped between secrets. Rotating the master key only re-wraps the DEKs, so
+// secret ciphertexts never need to be re-encrypted.
+type Encryptor struct {
+	keys KeyProvider
+}
+
+// NewEncryptor creates an Encryptor that wraps room data keys with the provider's master key.
+func NewEncryptor(keys KeyProvider) *Encryptor {
+	return &Encryptor{keys: keys}
+}
+
+// NewRoomKey generates a fresh data key for the room and returns it wrapped by the current master key.
+func (e *Encryptor) NewRoomKey(ctx context.Context, roomID uuid.UUID) (RoomKey, error) {
+	dek := make([]byte, dataKeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return RoomKey{}, err
+	}
+
+	return e.wrapRoomKey(ctx, dek, roomID)
+}
+
+// CurrentKeyVersion returns the master key version new room keys are wrapped with.
+func (e *Encryptor) CurrentKeyVersion(ctx context.Context) (int32, error) {
+	version, _, err := e.keys.CurrentKey(ctx)
+	return version, err
+}
+
+// RewrapRoomKey unwraps the room data key with its original master key and wraps it again with
+// the current one. The data key itself, and therefore every secret of the room, is unchanged.
+func (e *Encryptor) RewrapRoomKey(ctx context.Context, key RoomKey, roomID uuid.UUID) (RoomKey, error) {
+	dek, err := e.unwrapRoomKey(ctx, key, roomID)
+	if err != nil {
+		return RoomKey{}, err
+	}
+
+	return e.wrapRoomKey(ctx, dek, roomID)
+}
+
+// EncryptSecret seals the plaintext under the room data key and returns the ciphertext and nonce.
+func (e *Encryptor) EncryptSecret(
+	ctx context.Context,
+	key RoomKey,
+	roomID uuid.UUID,
+	secretID uuid.UUID,
+	plaintext []byte,
+) ([]byte, []byte, error) {
+	dek, err := e.unwrapRoomKey(ctx, key, roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce, ciphertext, err := seal(dek, plaintext, secretAAD(roomID, secretID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ciphertext, nonce, nil
+}
+
+// DecryptSecret opens a ciphertext produced by EncryptSecret for the same room and secret.
+func (e *Encryptor) DecryptSecret(
+	ctx context.Context,
+	key RoomKey,
+	roomID uuid.UUID,
+	secretID uuid.UUID,
+	ciphertext []byte,
+	nonce []byte,
+) ([]byte, error) {
+	dek, err := e.unwrapRoomKey(ctx, key, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(dek, nonce, ciphertext, secretAAD(roomID, secretID))
+}
+
+func (e *Encryptor) wrapRoomKey(ctx context.Context, dek []byte, roomID uuid.UUID) (RoomKey, error) {
+	version, kek, err := e.keys.CurrentKey(ctx)
+	if err != nil {
+		return RoomKey{}, err
+	}
+
+	nonce, sealed, err := seal(kek, dek, roomID[:])
+	if err != nil {
+		return RoomKey{}, err
+	}
+
+	return RoomKey{Wrapped: append(nonce, sealed...), Version: version}, nil
+}
+
+func (e *Encryptor) unwrapRoomKey(ctx context.Context, key RoomKey, roomID uuid.UUID) ([]byte, error) {
+	kek, err := e.keys.Key(ctx, key.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key.Wrapped) < gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+
+	return open(kek, key.Wrapped[:gcm.NonceSize()], key.Wrapped[gcm.NonceSize():], roomID[:])
+}
+
+func secretAAD(roomID uuid.UUID, secretID uuid.UUID) []byte {
+	aad := make([]byte, 0, len(roomID)+len(secretID))
+	aad = append(aad, roomID[:]...)
+	return append(aad, secretID[:]...)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func seal(key []byte, plaintext []byte, aad []byte) ([]byte, []byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return nonce, gcm.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func open(key []byte, nonce []byte, ciphertext []byte, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
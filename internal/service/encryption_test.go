@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testMasterKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generate master key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func testKeyRing(t *testing.T, spec string, current int32) *KeyRing {
+	t.Helper()
+
+	ring, err := ParseKeyRing(spec, current)
+	if err != nil {
+		t.Fatalf("parse key ring: %v", err)
+	}
+
+	return ring
+}
+
+func TestEncryptorRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	encryptor := NewEncryptor(testKeyRing(t, testMasterKey(t), 0))
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "text", plaintext: []byte("correct horse battery staple")},
+		{name: "empty", plaintext: []byte{}},
+		{name: "binary", plaintext: []byte{0x00, 0xff, 0x10, 0x80}},
+		{name: "large", plaintext: bytes.Repeat([]byte("x"), 64*1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roomID, secretID := uuid.New(), uuid.New()
+
+			key, err := encryptor.NewRoomKey(ctx, roomID)
+			if err != nil {
+				t.Fatalf("NewRoomKey: %v", err)
+			}
+
+			ciphertext, nonce, err := encryptor.EncryptSecret(ctx, key, roomID, secretID, tt.plaintext)
+			if err != nil {
+				t.Fatalf("EncryptSecret: %v", err)
+			}
+			if len(nonce) != SecretNonceSize {
+				t.Fatalf("nonce is %d bytes, want %d", len(nonce), SecretNonceSize)
+			}
+
+			plaintext, err := encryptor.DecryptSecret(ctx, key, roomID, secretID, ciphertext, nonce)
+			if err != nil {
+				t.Fatalf("DecryptSecret: %v", err)
+			}
+			if !bytes.Equal(plaintext, tt.plaintext) {
+				t.Fatalf("DecryptSecret = %q, want %q", plaintext, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptorRejectsWrongContext(t *testing.T) {
+	ctx := context.Background()
+	encryptor := NewEncryptor(testKeyRing(t, testMasterKey(t), 0))
+
+	roomID, secretID := uuid.New(), uuid.New()
+	key, err := encryptor.NewRoomKey(ctx, roomID)
+	if err != nil {
+		t.Fatalf("NewRoomKey: %v", err)
+	}
+	ciphertext, nonce, err := encryptor.EncryptSecret(ctx, key, roomID, secretID, []byte("secret"))
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+
+	otherRoomID := uuid.New()
+	otherRoomKey, err := encryptor.NewRoomKey(ctx, otherRoomID)
+	if err != nil {
+		t.Fatalf("NewRoomKey: %v", err)
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[0] ^= 0xff
+
+	tests := []struct {
+		name       string
+		key        RoomKey
+		roomID     uuid.UUID
+		secretID   uuid.UUID
+		ciphertext []byte
+		nonce      []byte
+	}{
+		{name: "other secret", key: key, roomID: roomID, secretID: uuid.New(), ciphertext: ciphertext, nonce: nonce},
+		{name: "other room id", key: key, roomID: otherRoomID, secretID: secretID, ciphertext: ciphertext, nonce: nonce},
+		{name: "other room key", key: otherRoomKey, roomID: otherRoomID, secretID: secretID, ciphertext: ciphertext, nonce: nonce},
+		{name: "tampered ciphertext", key: key, roomID: roomID, secretID: secretID, ciphertext: tampered, nonce: nonce},
+		{name: "short nonce", key: key, roomID: roomID, secretID: secretID, ciphertext: ciphertext, nonce: nonce[:8]},
+		{name: "truncated wrapped key", key: RoomKey{Wrapped: key.Wrapped[:4], Version: key.Version}, roomID: roomID, secretID: secretID, ciphertext: ciphertext, nonce: nonce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := encryptor.DecryptSecret(ctx, tt.key, tt.roomID, tt.secretID, tt.ciphertext, tt.nonce)
+			if !errors.Is(err, ErrDecryptionFailed) {
+				t.Fatalf("DecryptSecret error = %v, want %v", err, ErrDecryptionFailed)
+			}
+		})
+	}
+}
+
+func TestEncryptorRewrapRoomKey(t *testing.T) {
+	ctx := context.Background()
+	v1, v2 := testMasterKey(t), testMasterKey(t)
+	oldEncryptor := NewEncryptor(testKeyRing(t, "1:"+v1, 0))
+	newEncryptor := NewEncryptor(testKeyRing(t, "1:"+v1+",2:"+v2, 0))
+
+	roomID, secretID := uuid.New(), uuid.New()
+	key, err := oldEncryptor.NewRoomKey(ctx, roomID)
+	if err != nil {
+		t.Fatalf("NewRoomKey: %v", err)
+	}
+	ciphertext, nonce, err := oldEncryptor.EncryptSecret(ctx, key, roomID, secretID, []byte("secret"))
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+
+	rewrapped, err := newEncryptor.RewrapRoomKey(ctx, key, roomID)
+	if err != nil {
+		t.Fatalf("RewrapRoomKey: %v", err)
+	}
+	if rewrapped.Version != 2 {
+		t.Fatalf("rewrapped version = %d, want 2", rewrapped.Version)
+	}
+
+	plaintext, err := newEncryptor.DecryptSecret(ctx, rewrapped, roomID, secretID, ciphertext, nonce)
+	if err != nil {
+		t.Fatalf("DecryptSecret after rewrap: %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Fatalf("DecryptSecret = %q, want %q", plaintext, "secret")
+	}
+
+	if _, err := oldEncryptor.DecryptSecret(ctx, rewrapped, roomID, secretID, ciphertext, nonce); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Fatalf("DecryptSecret with retired ring error = %v, want %v", err, ErrUnknownKeyVersion)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"golang.org/x/oauth2"
+)
+
+// ErrProviderResponse is returned when an identity provider answers with a non-200 status.
+var ErrProviderResponse = errors.New("unexpected identity provider response")
+
+// IdentityProvider is an external OAuth2 or OpenID Connect provider users can sign in with.
+type IdentityProvider interface {
+	// Name is the provider name used in routes and stored with users and sessions.
+	Name() string
+	// AuthCodeURL returns the provider URL the user is sent to in order to grant access.
+	AuthCodeURL(ctx context.Context, flow *AuthFlow) (string, error)
+	// Exchange trades the authorization code returned to the callback for a token, proving
+	// possession of the flow's PKCE code verifier.
+	Exchange(ctx context.Context, code string, flow *AuthFlow) (*oauth2.Token, error)
+	// FetchIdentity returns the identity of the user the token was issued for.
+	FetchIdentity(ctx context.Context, token *oauth2.Token, flow *AuthFlow) (*dto.UserInfoResponseDto, error)
+}
+
+// IdentityProviders is a registry of the identity providers enabled for the API, indexed by name.
+type IdentityProviders struct {
+	providers map[string]IdentityProvider
+}
+
+// NewIdentityProviders builds the registry from the configuration. Google and GitHub are enabled
+// when their client ID is set, and every provider listed in OIDC_PROVIDERS is added.
+func NewIdentityProviders(cfg *configs.Conf) *IdentityProviders {
+	registry := &IdentityProviders{providers: map[string]IdentityProvider{}}
+
+	if cfg.GoogleClientID != "" {
+		registry.Register(NewGoogleProvider(cfg))
+	}
+	if cfg.GithubClientID != "" {
+		registry.Register(NewGithubProvider(cfg))
+	}
+	for _, conf := range cfg.OIDCProviders {
+		registry.Register(NewOIDCProvider(conf, cfg.RedirectURL(conf.Name)))
+	}
+
+	return registry
+}
+
+// Register adds a provider, replacing any provider previously registered under the same name.
+func (r *IdentityProviders) Register(provider IdentityProvider) {
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider registered under name.
+func (r *IdentityProviders) Get(name string) (IdentityProvider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// Names returns the names of the registered providers in alphabetical order.
+func (r *IdentityProviders) Names() []string {
+	return slices.Sorted(maps.Keys(r.providers))
+}
+
+// oauth2Provider implements the authorization code flow shared by every provider whose
+// endpoints are known up front.
+type oauth2Provider struct {
+	name   string
+	config *oauth2.Config
+}
+
+func (p *oauth2Provider) Name() string {
+	return p.name
+}
+
+func (p *oauth2Provider) AuthCodeURL(_ context.Context, flow *AuthFlow) (string, error) {
+	return authCodeURL(p.config, flow), nil
+}
+
+func (p *oauth2Provider) Exchange(ctx context.Context, code string, flow *AuthFlow) (*oauth2.Token, error) {
+	return p.config.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+}
+
+func authCodeURL(config *oauth2.Config, flow *AuthFlow, opts ...oauth2.AuthCodeOption) string {
+	opts = append(opts, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(flow.Verifier))
+	return config.AuthCodeURL(flow.State, opts...)
+}
+
+// fetchJSON performs an authenticated GET against a provider API and decodes the JSON response into out.
+func fetchJSON(ctx context.Context, url string, token *oauth2.Token, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: GET %s returned %d", ErrProviderResponse, url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
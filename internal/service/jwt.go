@@ -2,6 +2,8 @@
 package service
 
 import (
+	"errors"
+	"slices"
 	"time"
 
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
@@ -9,13 +11,42 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenerateToken creates a JWT token for the given user ID with an expiration time defined in the config.
-func GenerateToken(userID uuid.UUID, cfg *configs.Conf) (string, error) {
+const tokenIssuer = "vanish-vault-api"
+
+// ErrInvalidToken is returned when a JWT fails signature or claims validation.
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenClaims holds the validated claims extracted from a VanishVault JWT or personal access token.
+// Scopes is nil for session tokens, which carry every scope, and SessionID is the zero UUID for
+// personal access tokens.
+type TokenClaims struct {
+	ID        string
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+// HasScope reports whether the token grants the scope.
+func (c *TokenClaims) HasScope(scope string) bool {
+	return c.Scopes == nil || slices.Contains(c.Scopes, scope)
+}
+
+// IsPersonalAccessToken reports whether the claims belong to a personal access token rather than a login session.
+func (c *TokenClaims) IsPersonalAccessToken() bool {
+	return c.SessionID == uuid.Nil
+}
+
+// GenerateToken creates a JWT token for the given user ID and session with an expiration time defined in the config.
+func GenerateToken(userID uuid.UUID, sessionID uuid.UUID, cfg *configs.Conf) (string, error) {
 	claims := jwt.MapClaims{
+		"jti": uuid.NewString(),
 		"sub": userID.String(),
+		"sid": sessionID.String(),
 		"exp": time.Now().Add(time.Hour * time.Duration(cfg.JWTExpirationHours)).Unix(),
 		"iat": time.Now().Unix(),
-		"iss": "vanish-vault-api",
+		"iss": tokenIssuer,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -24,3 +55,60 @@ func GenerateToken(userID uuid.UUID, cfg *configs.Conf) (string, error) {
 
 	return token.SignedString(secret)
 }
+
+// ParseToken verifies the signature, expiration, issued-at and issuer of a JWT
+// created by GenerateToken and returns its claims.
+func ParseToken(tokenString string, cfg *configs.Conf) (*TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, func(*jwt.Token) (any, error) {
+		return []byte(cfg.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	sub, err := token.Claims.GetSubject()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	iat, err := token.Claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return nil, errors.Join(ErrInvalidToken, errors.New("token is missing iat claim"))
+	}
+
+	exp, err := token.Claims.GetExpirationTime()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	mapClaims, _ := token.Claims.(jwt.MapClaims)
+
+	jti, ok := mapClaims["jti"].(string)
+	if !ok || jti == "" {
+		return nil, errors.Join(ErrInvalidToken, errors.New("token is missing jti claim"))
+	}
+
+	sid, _ := mapClaims["sid"].(string)
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	return &TokenClaims{
+		ID:        jti,
+		UserID:    userID,
+		SessionID: sessionID,
+		IssuedAt:  iat.Time,
+		ExpiresAt: exp.Time,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+)
+
+const masterKeySize = 32
+
+var (
+	// ErrMasterKeyNotConfigured is returned when neither MASTER_KEY nor MASTER_KEY_FILE is set.
+	ErrMasterKeyNotConfigured = errors.New("master key is not configured")
+	// ErrUnknownKeyVersion is returned when a wrapped key references a master key that is not loaded.
+	ErrUnknownKeyVersion = errors.New("unknown master key version")
+)
+
+// KeyProvider supplies the versioned master key-encryption keys (KEK) used to wrap room data keys.
+// New data keys are always wrapped with the current version, while older versions stay
+// readable until every room has been re-wrapped.
+type KeyProvider interface {
+	CurrentKey(ctx context.Context) (int32, []byte, error)
+	Key(ctx context.Context, version int32) ([]byte, error)
+}
+
+// KeyRing is a KeyProvider holding every configured master key version in memory.
+type KeyRing struct {
+	keys    map[int32][]byte
+	current int32
+}
+
+// ParseKeyRing builds a KeyRing from a comma or newline separated list of "version:base64key"
+// entries. A single bare base64 key is treated as version 1. When current is zero the highest
+// version becomes the current one.
+func ParseKeyRing(spec string, current int32) (*KeyRing, error) {
+	entries := strings.FieldsFunc(spec, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	keys := make(map[int32][]byte, len(entries))
+	var highest int32
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		version := int32(1)
+		encoded := entry
+		if v, k, found := strings.Cut(entry, ":"); found {
+			parsed, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid master key version %q", v)
+			}
+			version = int32(parsed)
+			encoded = k
+		}
+
+		key, err := decodeMasterKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key version %d: %w", version, err)
+		}
+		if _, exists := keys[version]; exists {
+			return nil, fmt.Errorf("duplicate master key version %d", version)
+		}
+
+		keys[version] = key
+		highest = max(highest, version)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrMasterKeyNotConfigured
+	}
+	if current == 0 {
+		current = highest
+	}
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: current version %d", ErrUnknownKeyVersion, current)
+	}
+
+	return &KeyRing{keys: keys, current: current}, nil
+}
+
+// NewEnvKeyProvider creates a KeyProvider from the key ring spec held in MASTER_KEY.
+func NewEnvKeyProvider(spec string, current int32) (*KeyRing, error) {
+	return ParseKeyRing(spec, current)
+}
+
+// NewFileKeyProvider creates a KeyProvider from a file holding the key ring spec,
+// such as a Docker or Kubernetes secret mount.
+func NewFileKeyProvider(path string, current int32) (*KeyRing, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read master key file: %w", err)
+	}
+
+	return ParseKeyRing(string(data), current)
+}
+
+// NewKeyProvider builds the KeyProvider selected by the configuration, preferring MASTER_KEY_FILE.
+func NewKeyProvider(cfg *configs.Conf) (KeyProvider, error) {
+	switch {
+	case cfg.MasterKeyFile != "":
+		return NewFileKeyProvider(cfg.MasterKeyFile, cfg.MasterKeyVersion)
+	case cfg.MasterKey != "":
+		return NewEnvKeyProvider(cfg.MasterKey, cfg.MasterKeyVersion)
+	default:
+		return nil, ErrMasterKeyNotConfigured
+	}
+}
+
+// CurrentKey implements KeyProvider.
+func (r *KeyRing) CurrentKey(context.Context) (int32, []byte, error) {
+	return r.current, r.keys[r.current], nil
+}
+
+// Key implements KeyProvider.
+func (r *KeyRing) Key(_ context.Context, version int32) ([]byte, error) {
+	key, ok := r.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, version)
+	}
+
+	return key, nil
+}
+
+func decodeMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode master key: %w", err)
+	}
+
+	if len(key) != masterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", masterKeySize, len(key))
+	}
+
+	return key, nil
+}
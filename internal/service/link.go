@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const pendingLinkKeyPrefix = "identity:link:"
+
+// ErrPendingLinkNotFound is returned when a link confirmation token is unknown, expired or already used.
+var ErrPendingLinkNotFound = errors.New("pending identity link not found")
+
+// PendingLink is a provider identity waiting to be linked to a user. A link flow can be started by
+// anyone holding an access token, so the identity is only linked once the person who logged in with
+// the provider has seen which account it is joining and confirmed it.
+type PendingLink struct {
+	UserID   uuid.UUID               `json:"user_id"`
+	Provider string                  `json:"provider"`
+	Identity dto.UserInfoResponseDto `json:"identity"`
+}
+
+// SavePendingLink stores the link until it is confirmed or ttl passes, and returns the
+// single-use confirmation token.
+func SavePendingLink(ctx context.Context, rdb *redis.Client, link *PendingLink, ttl time.Duration) (string, error) {
+	token, err := GenerateRandomState()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		return "", err
+	}
+
+	if err := rdb.Set(ctx, pendingLinkKeyPrefix+hashOpaqueToken(token), data, ttl).Err(); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConsumePendingLink atomically fetches and deletes the link identified by token, so each
+// confirmation can be used only once.
+func ConsumePendingLink(ctx context.Context, rdb *redis.Client, token string) (*PendingLink, error) {
+	data, err := rdb.GetDel(ctx, pendingLinkKeyPrefix+hashOpaqueToken(token)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrPendingLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var link PendingLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
@@ -1,22 +1,26 @@
 package service
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"context"
 	"strconv"
 
 	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
 	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
-	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/google"
 )
 
+const (
+	googleUserInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
+	githubUserURL     = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
 type googleUser struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
 }
 
 type githubUser struct {
@@ -25,94 +29,120 @@ type githubUser struct {
 	Login string `json:"login"`
 }
 
-// GetOauthConfig returns the OAuth2 configuration for the specified provider.
-// It supports "google" and "github" providers based on the application configuration.
-func GetOauthConfig(provider string, cfg *configs.Conf) *oauth2.Config {
-	switch provider {
-	case "google":
-		return &oauth2.Config{
-			ClientID:     cfg.GoogleClientID,
-			ClientSecret: cfg.GoogleSecret,
-			RedirectURL:  "http://localhost:8080/api/v1/auth/callback/google",
-			Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
-			Endpoint:     google.Endpoint,
-		}
-	case "github":
-		return &oauth2.Config{
-			ClientID:     cfg.GithubClientID,
-			ClientSecret: cfg.GithubSecret,
-			RedirectURL:  "http://localhost:8080/api/v1/auth/callback/github",
-			Scopes:       []string{"user:email"},
-			Endpoint:     github.Endpoint,
-		}
-	default:
-		return nil
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// GoogleProvider signs users in with their Google account.
+type GoogleProvider struct {
+	oauth2Provider
+	UserInfoURL string
+}
+
+// NewGoogleProvider creates a GoogleProvider from the GOOGLE_* configuration.
+func NewGoogleProvider(cfg *configs.Conf) *GoogleProvider {
+	return &GoogleProvider{
+		oauth2Provider: oauth2Provider{
+			name: "google",
+			config: &oauth2.Config{
+				ClientID:     cfg.GoogleClientID,
+				ClientSecret: cfg.GoogleSecret,
+				RedirectURL:  cfg.RedirectURL("google"),
+				Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
+				Endpoint:     google.Endpoint,
+			},
+		},
+		UserInfoURL: googleUserInfoURL,
 	}
 }
 
-// FetchUserInfo retrieves profile information from OAuth2 providers.
-// It uses the provided zap.Logger for consistent structured logging.
-func FetchUserInfo(
-	provider string,
-	token string,
-	log *zap.Logger,
-) (*dto.UserInfoResponseDto, error) {
-	urls := map[string]string{
-		"google": "https://www.googleapis.com/oauth2/v2/userinfo",
-		"github": "https://api.github.com/user",
+// FetchIdentity implements IdentityProvider.
+func (p *GoogleProvider) FetchIdentity(ctx context.Context, token *oauth2.Token, _ *AuthFlow) (*dto.UserInfoResponseDto, error) {
+	var u googleUser
+	if err := fetchJSON(ctx, p.UserInfoURL, token, &u); err != nil {
+		return nil, err
 	}
 
-	url, ok := urls[provider]
-	if !ok {
-		log.Error("Unsupported OAuth provider requested", zap.String("provider", provider))
-		return nil, errors.New("unsupported provider")
+	return &dto.UserInfoResponseDto{
+		ID:            u.ID,
+		Email:         u.Email,
+		EmailVerified: u.VerifiedEmail,
+	}, nil
+}
+
+// GithubProvider signs users in with their GitHub account.
+type GithubProvider struct {
+	oauth2Provider
+	UserURL   string
+	EmailsURL string
+}
+
+// NewGithubProvider creates a GithubProvider from the GITHUB_* configuration.
+func NewGithubProvider(cfg *configs.Conf) *GithubProvider {
+	return &GithubProvider{
+		oauth2Provider: oauth2Provider{
+			name: "github",
+			config: &oauth2.Config{
+				ClientID:     cfg.GithubClientID,
+				ClientSecret: cfg.GithubSecret,
+				RedirectURL:  cfg.RedirectURL("github"),
+				Scopes:       []string{"user:email"},
+				Endpoint:     github.Endpoint,
+			},
+		},
+		UserURL:   githubUserURL,
+		EmailsURL: githubEmailsURL,
 	}
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Error("Failed to create HTTP request", zap.Error(err))
+// FetchIdentity implements IdentityProvider. The profile only carries the public email, which is
+// empty for users keeping their address private, so the email is taken from the emails API instead.
+func (p *GithubProvider) FetchIdentity(ctx context.Context, token *oauth2.Token, _ *AuthFlow) (*dto.UserInfoResponseDto, error) {
+	var u githubUser
+	if err := fetchJSON(ctx, p.UserURL, token, &u); err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Error("Failed to execute request to provider",
-			zap.String("provider", provider),
-			zap.Error(err),
-		)
+
+	var emails []githubEmail
+	if err := fetchJSON(ctx, p.EmailsURL, token, &emails); err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Error("Provider returned error status",
-			zap.Int("status", resp.StatusCode),
-			zap.String("provider", provider),
-		)
-		return nil, err
+	userInfo := &dto.UserInfoResponseDto{
+		ID:    strconv.Itoa(u.ID),
+		Email: u.Email,
 	}
+	if email, ok := githubPrimaryEmail(emails); ok {
+		userInfo.Email = email.Email
+		userInfo.EmailVerified = email.Verified
+	}
+
+	return userInfo, nil
+}
 
-	if provider == "google" {
-		var u googleUser
-		if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
-			log.Error("Failed to decode Google user response", zap.Error(err))
-			return nil, err
+// githubPrimaryEmail picks the verified primary address, falling back to any verified address
+// and then to the unverified primary one.
+func githubPrimaryEmail(emails []githubEmail) (githubEmail, bool) {
+	var verified, primary *githubEmail
+	for i := range emails {
+		switch {
+		case emails[i].Primary && emails[i].Verified:
+			return emails[i], true
+		case emails[i].Verified && verified == nil:
+			verified = &emails[i]
+		case emails[i].Primary:
+			primary = &emails[i]
 		}
-		return &dto.UserInfoResponseDto{
-			ID:    u.ID,
-			Email: u.Email,
-		}, nil
 	}
 
-	var u githubUser
-	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
-		log.Error("Failed to decode GitHub user response", zap.Error(err))
-		return nil, err
+	if verified != nil {
+		return *verified, true
+	}
+	if primary != nil {
+		return *primary, true
 	}
 
-	return &dto.UserInfoResponseDto{
-		ID:    strconv.Itoa(u.ID),
-		Email: u.Email,
-	}, nil
+	return githubEmail{}, false
 }
@@ -0,0 +1,55 @@
+package service
+
+import "testing"
+
+func TestGithubPrimaryEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		emails []githubEmail
+		want   githubEmail
+		wantOK bool
+	}{
+		{name: "no emails"},
+		{
+			name: "verified primary",
+			emails: []githubEmail{
+				{Email: "other@example.com", Verified: true},
+				{Email: "primary@example.com", Primary: true, Verified: true},
+			},
+			want:   githubEmail{Email: "primary@example.com", Primary: true, Verified: true},
+			wantOK: true,
+		},
+		{
+			name: "verified secondary before unverified primary",
+			emails: []githubEmail{
+				{Email: "primary@example.com", Primary: true},
+				{Email: "first@example.com", Verified: true},
+				{Email: "second@example.com", Verified: true},
+			},
+			want:   githubEmail{Email: "first@example.com", Verified: true},
+			wantOK: true,
+		},
+		{
+			name: "unverified primary",
+			emails: []githubEmail{
+				{Email: "other@example.com"},
+				{Email: "primary@example.com", Primary: true},
+			},
+			want:   githubEmail{Email: "primary@example.com", Primary: true},
+			wantOK: true,
+		},
+		{
+			name:   "only unverified secondary",
+			emails: []githubEmail{{Email: "other@example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := githubPrimaryEmail(tt.emails)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("githubPrimaryEmail = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
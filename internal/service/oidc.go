@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"sync"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/dto"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrMissingIDToken is returned when an OIDC token response carries no ID token.
+	ErrMissingIDToken = errors.New("token response has no id_token")
+	// ErrNonceMismatch is returned when the ID token nonce differs from the one sent at login.
+	ErrNonceMismatch = errors.New("id_token nonce does not match")
+)
+
+type oidcClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// OIDCProvider is a generic OpenID Connect provider configured by issuer URL. Its endpoints are
+// discovered from the issuer's .well-known/openid-configuration on first use and cached, and
+// users are identified by verifying the ID token rather than calling a userinfo endpoint.
+type OIDCProvider struct {
+	conf        configs.OIDCProviderConf
+	redirectURL string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider creates an OIDCProvider. Discovery is deferred until the provider is first used,
+// so an unreachable issuer does not prevent the API from starting.
+func NewOIDCProvider(conf configs.OIDCProviderConf, redirectURL string) *OIDCProvider {
+	return &OIDCProvider{
+		conf:        conf,
+		redirectURL: redirectURL,
+	}
+}
+
+// Name implements IdentityProvider.
+func (p *OIDCProvider) Name() string {
+	return p.conf.Name
+}
+
+// AuthCodeURL implements IdentityProvider. The flow's nonce is sent so the provider embeds it in the ID token.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, flow *AuthFlow) (string, error) {
+	config, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return authCodeURL(config, flow, oidc.Nonce(flow.Nonce)), nil
+}
+
+// Exchange implements IdentityProvider.
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, flow *AuthFlow) (*oauth2.Token, error) {
+	config, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+}
+
+// oauthConfig returns the OAuth2 configuration built from the discovered provider endpoints.
+func (p *OIDCProvider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	provider, _, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.conf.ClientID,
+		ClientSecret: p.conf.ClientSecret,
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.conf.Scopes,
+		Endpoint:     provider.Endpoint(),
+	}, nil
+}
+
+// FetchIdentity implements IdentityProvider. It verifies the signature, issuer, audience, expiry
+// and nonce of the ID token returned by the token exchange and reads the identity from its claims.
+func (p *OIDCProvider) FetchIdentity(
+	ctx context.Context,
+	token *oauth2.Token,
+	flow *AuthFlow,
+) (*dto.UserInfoResponseDto, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	_, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(flow.Nonce)) != 1 {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &dto.UserInfoResponseDto{
+		ID:            claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidc.Provider, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.provider, p.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.conf.IssuerURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.provider = provider
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.conf.ClientID})
+
+	return p.provider, p.verifier, nil
+}
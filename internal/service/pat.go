@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// PersonalAccessTokenPrefix marks personal access tokens, so they are told apart from JWTs and
+// can be found by secret scanners.
+const PersonalAccessTokenPrefix = "vvpat_"
+
+// Scopes a personal access token can be granted.
+const (
+	ScopeRoomsRead    = "rooms:read"
+	ScopeRoomsWrite   = "rooms:write"
+	ScopeSecretsRead  = "secrets:read"
+	ScopeSecretsWrite = "secrets:write"
+)
+
+// PersonalAccessTokenScopes lists every scope a personal access token can be granted.
+var PersonalAccessTokenScopes = []string{ScopeRoomsRead, ScopeRoomsWrite, ScopeSecretsRead, ScopeSecretsWrite}
+
+// GeneratePersonalAccessToken creates a new personal access token and returns it together with
+// its hash. Only the hash is persisted; the plain token is shown to the user once.
+func GeneratePersonalAccessToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token := PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashPersonalAccessToken(token), nil
+}
+
+// HashPersonalAccessToken returns the SHA-256 digest used to look up a personal access token in storage.
+func HashPersonalAccessToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// IsPersonalAccessToken reports whether the bearer token is a personal access token.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// NormalizeScopes sorts and deduplicates the requested scopes.
+func NormalizeScopes(scopes []string) []string {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// AuthenticatePersonalAccessToken looks up an active personal access token, records its use and
+// returns the claims it grants.
+func AuthenticatePersonalAccessToken(
+	ctx context.Context,
+	repo repository.Querier,
+	token string,
+) (*TokenClaims, error) {
+	pat, err := repo.UsePersonalAccessToken(ctx, HashPersonalAccessToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenClaims{
+		ID:        pat.ID.String(),
+		UserID:    pat.UserID,
+		IssuedAt:  pat.CreatedAt.Time,
+		ExpiresAt: pat.ExpiresAt.Time,
+		Scopes:    pat.Scopes,
+	}, nil
+}
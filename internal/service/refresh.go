@@ -0,0 +1,26 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// GenerateRefreshToken creates a new opaque refresh token and returns it together with its hash.
+// Only the hash is meant to be persisted; the plain token is handed to the client once.
+func GenerateRefreshToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the SHA-256 digest used to look up a refresh token in storage.
+func HashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
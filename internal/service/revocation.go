@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	revokedTokenKeyPrefix   = "revoked:jti:"
+	revokedSessionKeyPrefix = "revoked:sid:"
+	revokedUserKeyPrefix    = "revoked:user:"
+)
+
+// RevokeToken adds the token identified by jti to the revocation list until it would have expired anyway.
+func RevokeToken(ctx context.Context, rdb *redis.Client, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return rdb.Set(ctx, revokedTokenKeyPrefix+jti, 1, ttl).Err()
+}
+
+// RevokeSessionTokens marks every access token bound to the session as revoked.
+// The entry only needs to outlive the longest-lived access token issued for the session.
+func RevokeSessionTokens(ctx context.Context, rdb *redis.Client, sessionID uuid.UUID, ttl time.Duration) error {
+	return rdb.Set(ctx, revokedSessionKeyPrefix+sessionID.String(), 1, ttl).Err()
+}
+
+// RevokeUserTokens marks every access token issued to the user until now as revoked, whatever
+// session it belongs to. Tokens issued afterwards are not affected.
+func RevokeUserTokens(ctx context.Context, rdb *redis.Client, userID uuid.UUID, ttl time.Duration) error {
+	return rdb.Set(ctx, revokedUserKeyPrefix+userID.String(), time.Now().Unix(), ttl).Err()
+}
+
+// IsTokenRevoked reports whether the token itself, the session it belongs to or every token of
+// its user issued before it has been revoked.
+func IsTokenRevoked(ctx context.Context, rdb *redis.Client, claims *TokenClaims) (bool, error) {
+	values, err := rdb.MGet(ctx,
+		revokedTokenKeyPrefix+claims.ID,
+		revokedSessionKeyPrefix+claims.SessionID.String(),
+		revokedUserKeyPrefix+claims.UserID.String(),
+	).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil || values[1] != nil {
+		return true, nil
+	}
+
+	cutoff, ok := values[2].(string)
+	if !ok {
+		return false, nil
+	}
+	revokedAt, err := strconv.ParseInt(cutoff, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	return claims.IssuedAt.Unix() <= revokedAt, nil
+}
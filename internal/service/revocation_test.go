@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIsTokenRevoked(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+
+	revokedUser := uuid.New()
+	revokedSession := uuid.New()
+	if err := RevokeUserTokens(ctx, rdb, revokedUser, time.Hour); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	if err := RevokeSessionTokens(ctx, rdb, revokedSession, time.Hour); err != nil {
+		t.Fatalf("RevokeSessionTokens: %v", err)
+	}
+	if err := RevokeToken(ctx, rdb, "revoked-jti", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims TokenClaims
+		want   bool
+	}{
+		{name: "active", claims: TokenClaims{ID: "jti", UserID: uuid.New(), SessionID: uuid.New(), IssuedAt: time.Now()}},
+		{name: "revoked jti", claims: TokenClaims{ID: "revoked-jti", UserID: uuid.New(), SessionID: uuid.New()}, want: true},
+		{name: "revoked session", claims: TokenClaims{ID: "jti", UserID: uuid.New(), SessionID: revokedSession}, want: true},
+		{
+			name:   "issued before the user was revoked",
+			claims: TokenClaims{ID: "jti", UserID: revokedUser, SessionID: uuid.New(), IssuedAt: time.Now().Add(-time.Minute)},
+			want:   true,
+		},
+		{
+			name:   "issued after the user was revoked",
+			claims: TokenClaims{ID: "jti", UserID: revokedUser, SessionID: uuid.New(), IssuedAt: time.Now().Add(2 * time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := IsTokenRevoked(ctx, rdb, &tt.claims)
+			if err != nil {
+				t.Fatalf("IsTokenRevoked: %v", err)
+			}
+			if revoked != tt.want {
+				t.Fatalf("IsTokenRevoked = %v, want %v", revoked, tt.want)
+			}
+		})
+	}
+}
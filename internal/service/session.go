@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/configs"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevokeSession revokes a single session of the user, its refresh token family and every
+// access token issued for it. It reports whether the session was found. Revoking an already
+// revoked session is not an error, so a retry after a failed Redis write revokes its tokens again.
+func RevokeSession(
+	ctx context.Context,
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	userID uuid.UUID,
+	sessionID uuid.UUID,
+) (bool, error) {
+	if _, err := repo.RevokeSession(ctx, repository.RevokeSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, revokeSessionCredentials(ctx, repo, rdb, cfg, sessionID)
+}
+
+// RevokeAllSessions revokes every active session of the user ("log out everywhere"). Sessions
+// revoked recently enough to still have live access tokens are revoked again and counted, so a
+// retry after a failed Redis write revokes their tokens too.
+func RevokeAllSessions(
+	ctx context.Context,
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	userID uuid.UUID,
+) (int, error) {
+	sessionIDs, err := repo.RevokeAllSessionsByUser(ctx, repository.RevokeAllSessionsByUserParams{
+		UserID:       userID,
+		RevokedAfter: accessTokenWindow(cfg),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := revokeSessionCredentials(ctx, repo, rdb, cfg, sessionID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(sessionIDs), nil
+}
+
+func revokeSessionCredentials(
+	ctx context.Context,
+	repo repository.Querier,
+	rdb *redis.Client,
+	cfg *configs.Conf,
+	sessionID uuid.UUID,
+) error {
+	if err := repo.RevokeRefreshTokenFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return RevokeSessionTokens(ctx, rdb, sessionID, time.Hour*time.Duration(cfg.JWTExpirationHours))
+}
+
+// accessTokenWindow returns the time before which every issued access token has expired.
+func accessTokenWindow(cfg *configs.Conf) pgtype.Timestamptz {
+	return pgtype.Timestamptz{
+		Time:  time.Now().Add(-time.Hour * time.Duration(cfg.JWTExpirationHours)),
+		Valid: true,
+	}
+}
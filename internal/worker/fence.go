@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/scheduler"
+)
+
+// execFenced runs fn in a transaction guarded by the fence of the scheduled job the context belongs
+// to. Outside of the scheduler, such as during a manual key rotation, fn runs in a plain transaction.
+func execFenced(ctx context.Context, store repository.Store, fn func(repository.Querier) error) error {
+	fence, ok := scheduler.FencingToken(ctx)
+	if !ok {
+		return store.ExecTx(ctx, fn)
+	}
+
+	return store.ExecFenced(ctx, fence.Job, fence.Token, fn)
+}
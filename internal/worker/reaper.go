@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Reaper enforces expiry and makes burned data vanish from the database. Expired rooms are
+// deactivated right away and deleted together with their secrets once the grace period has
+// passed, and burned secrets have their rows, ciphertext included, deleted after the same period.
+// Revoked and expired personal access tokens are deleted after the grace period too, as are
+// tokens without an explicit expiry left unused for longer than the idle timeout, when one is set.
+type Reaper struct {
+	store            repository.Store
+	log              *zap.Logger
+	gracePeriod      time.Duration
+	tokenIdleTimeout time.Duration
+}
+
+// ReapResult summarizes a reaper pass.
+type ReapResult struct {
+	RoomsDeactivated int64
+	RoomsDeleted     int64
+	SecretsBurned    int64
+	SecretsPurged    int64
+	TokensPurged     int64
+}
+
+// NewReaper creates a new Reaper. A non-positive tokenIdleTimeout keeps unused tokens forever.
+func NewReaper(
+	store repository.Store,
+	log *zap.Logger,
+	gracePeriod time.Duration,
+	tokenIdleTimeout time.Duration,
+) *Reaper {
+	return &Reaper{
+		store:            store,
+		log:              log,
+		gracePeriod:      gracePeriod,
+		tokenIdleTimeout: tokenIdleTimeout,
+	}
+}
+
+// Reap runs a single pass over expired rooms, burned secrets and stale personal access tokens.
+// The pass is applied in one transaction, guarded by the job's fencing token when run by the scheduler.
+func (r *Reaper) Reap(ctx context.Context) (ReapResult, error) {
+	var result ReapResult
+
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-r.gracePeriod), Valid: true}
+
+	var idleCutoff pgtype.Timestamptz
+	if r.tokenIdleTimeout > 0 {
+		idleCutoff = pgtype.Timestamptz{Time: time.Now().Add(-r.tokenIdleTimeout), Valid: true}
+	}
+
+	err := execFenced(ctx, r.store, func(q repository.Querier) error {
+		var err error
+		result = ReapResult{}
+
+		if result.RoomsDeactivated, err = q.DeactivateExpiredRooms(ctx); err != nil {
+			return err
+		}
+
+		if result.RoomsDeleted, err = q.DeleteExpiredRooms(ctx, cutoff); err != nil {
+			return err
+		}
+
+		if result.SecretsBurned, err = q.BurnExpiredSecrets(ctx); err != nil {
+			return err
+		}
+
+		if result.SecretsPurged, err = q.PurgeBurnedSecrets(ctx, cutoff); err != nil {
+			return err
+		}
+
+		result.TokensPurged, err = q.PurgePersonalAccessTokens(ctx, repository.PurgePersonalAccessTokensParams{
+			Cutoff:     cutoff,
+			IdleCutoff: idleCutoff,
+		})
+		return err
+	})
+	if err != nil {
+		return ReapResult{}, err
+	}
+
+	return result, nil
+}
+
+// RunOnce performs a single reaper pass and logs its outcome. It is meant to be scheduled periodically.
+func (r *Reaper) RunOnce(ctx context.Context) {
+	result, err := r.Reap(ctx)
+	if err != nil {
+		r.log.Error("Reaper pass failed", zap.Error(err))
+		return
+	}
+
+	if result != (ReapResult{}) {
+		r.log.Info("Reaper pass complete",
+			zap.Int64("rooms_deactivated", result.RoomsDeactivated),
+			zap.Int64("rooms_deleted", result.RoomsDeleted),
+			zap.Int64("secrets_burned", result.SecretsBurned),
+			zap.Int64("secrets_purged", result.SecretsPurged),
+			zap.Int64("tokens_purged", result.TokensPurged),
+		)
+	}
+}
@@ -0,0 +1,142 @@
+// Package worker contains background jobs that run alongside the API server.
+package worker
+
+import (
+	"context"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// KeyRewrapper re-wraps room data keys under the current master key version in batches.
+// Rooms keep working during the migration because older master key versions stay readable.
+type KeyRewrapper struct {
+	store     repository.Store
+	encryptor *service.Encryptor
+	log       *zap.Logger
+	batchSize int32
+}
+
+// RewrapResult summarizes a re-wrap pass.
+type RewrapResult struct {
+	Rewrapped int
+	Failed    int
+}
+
+// NewKeyRewrapper creates a new KeyRewrapper.
+func NewKeyRewrapper(
+	store repository.Store,
+	encryptor *service.Encryptor,
+	log *zap.Logger,
+	batchSize int32,
+) *KeyRewrapper {
+	return &KeyRewrapper{
+		store:     store,
+		encryptor: encryptor,
+		log:       log,
+		batchSize: batchSize,
+	}
+}
+
+// RewrapBatch re-wraps up to one batch of room keys that are not on the current master key version,
+// starting after the given room ID. It returns the ID of the last room visited, or uuid.Nil once no
+// rooms are left, so callers can page past rooms whose keys cannot be re-wrapped. Each room is
+// updated with a compare-and-swap on its key version, so concurrent runs never overwrite each other,
+// and the updates of a batch are guarded by the job's fencing token when run by the scheduler.
+func (w *KeyRewrapper) RewrapBatch(ctx context.Context, after uuid.UUID) (RewrapResult, uuid.UUID, error) {
+	var result RewrapResult
+
+	current, err := w.encryptor.CurrentKeyVersion(ctx)
+	if err != nil {
+		return result, uuid.Nil, err
+	}
+
+	rooms, err := w.store.ListRoomsForRewrap(ctx, repository.ListRoomsForRewrapParams{
+		KeyVersion: current,
+		AfterID:    after,
+		BatchSize:  w.batchSize,
+	})
+	if err != nil || len(rooms) == 0 {
+		return result, uuid.Nil, err
+	}
+
+	rewrapped := make([]repository.RewrapRoomKeyParams, 0, len(rooms))
+	for _, room := range rooms {
+		key, err := w.encryptor.RewrapRoomKey(ctx, service.RoomKey{
+			Wrapped: room.WrappedKey,
+			Version: room.KeyVersion,
+		}, room.ID)
+		if err != nil {
+			w.log.Error("Failed to re-wrap room key",
+				zap.String("room_id", room.ID.String()),
+				zap.Int32("key_version", room.KeyVersion),
+				zap.Error(err),
+			)
+			result.Failed++
+			continue
+		}
+
+		rewrapped = append(rewrapped, repository.RewrapRoomKeyParams{
+			WrappedKey:    key.Wrapped,
+			NewKeyVersion: key.Version,
+			ID:            room.ID,
+			OldKeyVersion: room.KeyVersion,
+		})
+	}
+
+	err = execFenced(ctx, w.store, func(q repository.Querier) error {
+		result.Rewrapped = 0
+		for _, params := range rewrapped {
+			updated, err := q.RewrapRoomKey(ctx, params)
+			if err != nil {
+				return err
+			}
+			if updated > 0 {
+				result.Rewrapped++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, uuid.Nil, err
+	}
+
+	return result, rooms[len(rooms)-1].ID, nil
+}
+
+// RewrapAll pages through every room key that is not on the current master key version. Keys that
+// cannot be unwrapped are counted as failed and skipped, so they never block the rooms after them.
+func (w *KeyRewrapper) RewrapAll(ctx context.Context) (RewrapResult, error) {
+	var total RewrapResult
+
+	after := uuid.Nil
+	for {
+		result, last, err := w.RewrapBatch(ctx, after)
+		total.Rewrapped += result.Rewrapped
+		total.Failed += result.Failed
+		if err != nil || last == uuid.Nil {
+			return total, err
+		}
+
+		after = last
+	}
+}
+
+// RunOnce re-wraps every outdated room key and logs the outcome. It is meant to be scheduled periodically.
+func (w *KeyRewrapper) RunOnce(ctx context.Context) {
+	result, err := w.RewrapAll(ctx)
+	if err != nil {
+		w.log.Error("Room key re-wrap failed", zap.Error(err))
+		return
+	}
+
+	if result.Rewrapped > 0 || result.Failed > 0 {
+		w.log.Info("Room keys re-wrapped",
+			zap.Int("rewrapped", result.Rewrapped),
+			zap.Int("failed", result.Failed),
+		)
+	}
+}
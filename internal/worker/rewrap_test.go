@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"slices"
+	"testing"
+
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/repository"
+	"github.com/TheCodeBreakerK/vanish-vault-api/internal/service"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// rewrapQuerier keeps room keys in memory and pages them by ID like ListRoomsForRewrap.
+type rewrapQuerier struct {
+	repository.Store
+	rooms []repository.ListRoomsForRewrapRow
+}
+
+func (q *rewrapQuerier) ExecTx(_ context.Context, fn func(repository.Querier) error) error {
+	return fn(q)
+}
+
+func (q *rewrapQuerier) ListRoomsForRewrap(
+	_ context.Context,
+	arg repository.ListRoomsForRewrapParams,
+) ([]repository.ListRoomsForRewrapRow, error) {
+	var rows []repository.ListRoomsForRewrapRow
+	for _, room := range q.rooms {
+		if room.KeyVersion != arg.KeyVersion && bytes.Compare(room.ID[:], arg.AfterID[:]) > 0 {
+			rows = append(rows, room)
+		}
+		if len(rows) == int(arg.BatchSize) {
+			break
+		}
+	}
+
+	return rows, nil
+}
+
+func (q *rewrapQuerier) RewrapRoomKey(_ context.Context, arg repository.RewrapRoomKeyParams) (int64, error) {
+	for i, room := range q.rooms {
+		if room.ID == arg.ID && room.KeyVersion == arg.OldKeyVersion {
+			q.rooms[i].WrappedKey = arg.WrappedKey
+			q.rooms[i].KeyVersion = arg.NewKeyVersion
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func testMasterKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generate master key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestRewrapAllSkipsFailedKeys(t *testing.T) {
+	ctx := context.Background()
+	v1, v2 := testMasterKey(t), testMasterKey(t)
+
+	oldRing, err := service.ParseKeyRing("1:"+v1, 0)
+	if err != nil {
+		t.Fatalf("parse key ring: %v", err)
+	}
+	newRing, err := service.ParseKeyRing("1:"+v1+",2:"+v2, 0)
+	if err != nil {
+		t.Fatalf("parse key ring: %v", err)
+	}
+	oldEncryptor := service.NewEncryptor(oldRing)
+
+	q := &rewrapQuerier{}
+	for range 5 {
+		id := uuid.New()
+		key, err := oldEncryptor.NewRoomKey(ctx, id)
+		if err != nil {
+			t.Fatalf("NewRoomKey: %v", err)
+		}
+		q.rooms = append(q.rooms, repository.ListRoomsForRewrapRow{ID: id, WrappedKey: key.Wrapped, KeyVersion: key.Version})
+	}
+	slices.SortFunc(q.rooms, func(a, b repository.ListRoomsForRewrapRow) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	// The first rooms fill whole batches with keys that can never be unwrapped.
+	q.rooms[0].WrappedKey = []byte("corrupt")
+	q.rooms[1].WrappedKey = []byte("corrupt")
+
+	rewrapper := NewKeyRewrapper(q, service.NewEncryptor(newRing), zap.NewNop(), 2)
+	result, err := rewrapper.RewrapAll(ctx)
+	if err != nil {
+		t.Fatalf("RewrapAll: %v", err)
+	}
+
+	if result.Rewrapped != 3 || result.Failed != 2 {
+		t.Fatalf("RewrapAll = %+v, want 3 re-wrapped and 2 failed", result)
+	}
+	for _, room := range q.rooms[2:] {
+		if room.KeyVersion != 2 {
+			t.Fatalf("room %s is on key version %d, want 2", room.ID, room.KeyVersion)
+		}
+	}
+}
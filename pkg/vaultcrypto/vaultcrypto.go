@@ -0,0 +1,163 @@
+// Package vaultcrypto is the reference client for zero-knowledge secrets. It encrypts content
+// locally so the API only ever stores ciphertext, and carries the key in the fragment of a share
+// link, which browsers and HTTP clients never send to the server.
+package vaultcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Algorithm is the identifier sent to the API alongside client-side ciphertexts.
+const Algorithm = "AES-256-GCM"
+
+// KeySize is the length in bytes of a secret key.
+const KeySize = 32
+
+const fragmentKeyParam = "key"
+
+var (
+	// ErrInvalidKey is returned when a key does not have KeySize bytes.
+	ErrInvalidKey = errors.New("vaultcrypto: key must be 32 bytes")
+	// ErrUnsupportedAlgorithm is returned when an envelope uses an algorithm other than Algorithm.
+	ErrUnsupportedAlgorithm = errors.New("vaultcrypto: unsupported algorithm")
+	// ErrDecryptionFailed is returned when the ciphertext cannot be authenticated with the key.
+	ErrDecryptionFailed = errors.New("vaultcrypto: decryption failed")
+	// ErrMissingKey is returned when a share link has no key in its fragment.
+	ErrMissingKey = errors.New("vaultcrypto: share link has no key")
+)
+
+// Envelope mirrors the encrypted fields of the create and read secret payloads.
+// Binary values are standard base64 encoded, as expected by the API.
+type Envelope struct {
+	EncryptedContent string `json:"encrypted_content"`
+	Nonce            string `json:"nonce"`
+	Algorithm        string `json:"algorithm"`
+}
+
+// GenerateKey returns a new random key for a single secret.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Seal encrypts the plaintext with the key and returns an Envelope ready to submit to the API.
+func Seal(key []byte, plaintext []byte) (*Envelope, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		EncryptedContent: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+		Nonce:            base64.StdEncoding.EncodeToString(nonce),
+		Algorithm:        Algorithm,
+	}, nil
+}
+
+// Open decrypts an Envelope returned by the API with the key from the share link.
+func Open(key []byte, env *Envelope) ([]byte, error) {
+	if env.Algorithm != Algorithm {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, env.Algorithm)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(env.EncryptedContent)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted_content: %w", err)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decode nonce: %w", err)
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+// ShareLink appends the key to the fragment of link, e.g. https://vault.example.com/s/<id>#key=...
+// Any existing fragment is replaced.
+func ShareLink(link string, key []byte) (string, error) {
+	if len(key) != KeySize {
+		return "", ErrInvalidKey
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	u.Fragment = fragmentKeyParam + "=" + base64.RawURLEncoding.EncodeToString(key)
+
+	return u.String(), nil
+}
+
+// ParseShareLink splits a share link into the link without its fragment and the key it carries.
+func ParseShareLink(link string) (string, []byte, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", nil, err
+	}
+
+	values, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse share link fragment: %w", err)
+	}
+
+	encoded := values.Get(fragmentKeyParam)
+	if encoded == "" {
+		return "", nil, ErrMissingKey
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return "", nil, fmt.Errorf("decode share link key: %w", err)
+	}
+	if len(key) != KeySize {
+		return "", nil, ErrInvalidKey
+	}
+
+	u.Fragment = ""
+
+	return u.String(), key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
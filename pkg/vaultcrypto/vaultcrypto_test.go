@@ -0,0 +1,126 @@
+package vaultcrypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "text", plaintext: []byte("correct horse battery staple")},
+		{name: "empty", plaintext: []byte{}},
+		{name: "binary", plaintext: []byte{0x00, 0xff, 0x10, 0x80}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := GenerateKey()
+			if err != nil {
+				t.Fatalf("GenerateKey: %v", err)
+			}
+
+			env, err := Seal(key, tt.plaintext)
+			if err != nil {
+				t.Fatalf("Seal: %v", err)
+			}
+			if env.Algorithm != Algorithm {
+				t.Fatalf("Algorithm = %q, want %q", env.Algorithm, Algorithm)
+			}
+
+			plaintext, err := Open(key, env)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if !bytes.Equal(plaintext, tt.plaintext) {
+				t.Fatalf("Open = %q, want %q", plaintext, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestOpenRejectsInvalidEnvelopes(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	env, err := Seal(key, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	other, err := Seal(key, []byte("other secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  []byte
+		env  Envelope
+		want error
+	}{
+		{name: "wrong key", key: otherKey, env: *env, want: ErrDecryptionFailed},
+		{name: "short key", key: key[:16], env: *env, want: ErrInvalidKey},
+		{name: "unsupported algorithm", key: key, env: Envelope{EncryptedContent: env.EncryptedContent, Nonce: env.Nonce, Algorithm: "ChaCha20-Poly1305"}, want: ErrUnsupportedAlgorithm},
+		{name: "swapped nonce", key: key, env: Envelope{EncryptedContent: env.EncryptedContent, Nonce: other.Nonce, Algorithm: Algorithm}, want: ErrDecryptionFailed},
+		{name: "short nonce", key: key, env: Envelope{EncryptedContent: env.EncryptedContent, Nonce: "AAAA", Algorithm: Algorithm}, want: ErrDecryptionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Open(tt.key, &tt.env); !errors.Is(err, tt.want) {
+				t.Fatalf("Open error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShareLinkRoundTrip(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	link, err := ShareLink("https://vault.example.com/s/123#old", key)
+	if err != nil {
+		t.Fatalf("ShareLink: %v", err)
+	}
+
+	base, parsed, err := ParseShareLink(link)
+	if err != nil {
+		t.Fatalf("ParseShareLink: %v", err)
+	}
+	if base != "https://vault.example.com/s/123" {
+		t.Fatalf("base = %q, want %q", base, "https://vault.example.com/s/123")
+	}
+	if !bytes.Equal(parsed, key) {
+		t.Fatal("parsed key does not match")
+	}
+}
+
+func TestParseShareLinkErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want error
+	}{
+		{name: "no fragment", link: "https://vault.example.com/s/123", want: ErrMissingKey},
+		{name: "short key", link: "https://vault.example.com/s/123#key=AAAA", want: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseShareLink(tt.link); !errors.Is(err, tt.want) {
+				t.Fatalf("ParseShareLink error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}